@@ -9,6 +9,7 @@ import (
 	crand "crypto/rand"
 	"encoding/hex"
 	"io/ioutil"
+	"math/big"
 	"sync"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 	"github.com/Conflux-Chain/go-conflux-sdk/utils"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 )
@@ -382,6 +385,24 @@ func (m *AccountManager) Sign(tx types.UnsignedTransaction, passphrase string) (
 	return v, r, s, nil
 }
 
+// ListESpace returns the eSpace addresses of all accounts, which are derived from the same private keys
+func (m *AccountManager) ListESpace() []common.Address {
+	var result []common.Address
+	for _, account := range m.ks.Accounts() {
+		result = append(result, account.Address)
+	}
+	return result
+}
+
+// SignESpaceTransaction signs eSpace tx by the key of eSpace address from, the account should be unlocked
+func (m *AccountManager) SignESpaceTransaction(from common.Address, tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	signed, err := m.ks.SignTx(accounts.Account{Address: from}, tx, chainID)
+	if err != nil {
+		return nil, errors.Wrap(err, errMsgSignTx)
+	}
+	return signed, nil
+}
+
 func getCfxUserAddress(account accounts.Account, networkID uint32) cfxaddress.Address {
 	account.Address[0] = account.Address[0]&0x1f | 0x10
 	cfxAddress := cfxaddress.MustNewFromCommon(account.Address, networkID)
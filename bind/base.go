@@ -200,7 +200,9 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *types.Address, in
 		Data:                    types.NewBytes(input),
 	}
 
-	c.transactor.ApplyUnsignedTransactionDefault(&utx)
+	if err := c.transactor.ApplyUnsignedTransactionDefault(&utx); err != nil {
+		return nil, nil, err
+	}
 
 	hash, err := c.transactor.SendTransaction(utx)
 	if err != nil {
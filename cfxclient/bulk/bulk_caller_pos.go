@@ -90,3 +90,23 @@ func (client *BulkPosCaller) GetRewardsByEpoch(epochNumber uint64) (*postypes.Ep
 	(*BulkCallerCore)(client).appendElemsAndError(elem, err)
 	return result, err
 }
+
+// GetEpochState returns the epoch state of epoch
+func (client *BulkPosCaller) GetEpochState(epochNumber uint64) (*postypes.EpochState, *error) {
+	result := new(postypes.EpochState)
+	err := new(error)
+
+	elem := newBatchElem(result, "pos_getEpochState", hexutil.Uint64(epochNumber))
+	(*BulkCallerCore)(client).appendElemsAndError(elem, err)
+	return result, err
+}
+
+// GetLedgerInfoByEpoch returns the epoch ending ledger info with signatures of epoch
+func (client *BulkPosCaller) GetLedgerInfoByEpoch(epochNumber uint64) (*postypes.LedgerInfoWithSignatures, *error) {
+	result := new(postypes.LedgerInfoWithSignatures)
+	err := new(error)
+
+	elem := newBatchElem(result, "pos_getLedgerInfoByEpoch", hexutil.Uint64(epochNumber))
+	(*BulkCallerCore)(client).appendElemsAndError(elem, err)
+	return result, err
+}
@@ -4,7 +4,6 @@ import (
 	"math/big"
 
 	sdk "github.com/Conflux-Chain/go-conflux-sdk"
-	"github.com/Conflux-Chain/go-conflux-sdk/constants"
 	"github.com/Conflux-Chain/go-conflux-sdk/types"
 	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -38,7 +37,7 @@ func (b *BulkSender) AppendTransaction(tx *types.UnsignedTransaction) *BulkSende
 // if set NONCE_TYPE_AUTO, it will use nonce when exist pending txs because of notEnoughCash/notEnoughCash/outDatedStatus/outOfEpochHeight/noncefuture
 // and use pending nonce when no pending txs.
 func (b *BulkSender) PopulateTransactions(nonceSource types.NonceType) ([]*types.UnsignedTransaction, error) {
-	defaultAccount, chainID, networkId, gasPrice, epochHeight, err := b.getChainInfos()
+	defaultAccount, chainID, networkId, epochHeight, err := b.getChainInfos()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -57,6 +56,11 @@ func (b *BulkSender) PopulateTransactions(nonceSource types.NonceType) ([]*types
 		return nil, err
 	}
 
+	fee, err := b.suggestFee()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to suggest fee")
+	}
+
 	// set nonce
 	userUsedNoncesMap := b.gatherUsedNonces()
 	userNextNonceCache, err := b.gatherInitNextNonces(nonceSource)
@@ -75,8 +79,8 @@ func (b *BulkSender) PopulateTransactions(nonceSource types.NonceType) ([]*types
 			utx.ChainID = chainID
 		}
 
-		if utx.GasPrice == nil {
-			utx.GasPrice = gasPrice
+		if err := sdk.ApplyFeeData(utx, fee); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply fee of the %vth transaction", i)
 		}
 
 		if utx.EpochHeight == nil {
@@ -287,11 +291,32 @@ func (b *BulkSender) checkIsNonceUsed(usedCaches map[string]map[string]bool, use
 	return false
 }
 
+// outbox returns the outbox of client at the time of sending, so that the outbox set after the bulk sender
+// created is used too.
+func (b *BulkSender) outbox() *sdk.Outbox {
+	if c, ok := b.signableCaller.(interface{ Outbox() *sdk.Outbox }); ok {
+		return c.Outbox()
+	}
+
+	return nil
+}
+
+// suggestFee returns the fee suggested by the client if it is able to, e.g. *sdk.Client, otherwise by
+// sdk.DefaultFeeStrategy.
+func (b *BulkSender) suggestFee() (*sdk.FeeData, error) {
+	if suggester, ok := b.signableCaller.(interface {
+		SuggestFee() (*sdk.FeeData, error)
+	}); ok {
+		return suggester.SuggestFee()
+	}
+
+	return sdk.DefaultFeeStrategy{}.SuggestFee(sdk.NewFeeOracle(b.signableCaller))
+}
+
 func (b *BulkSender) getChainInfos() (
 	defaultAccount *cfxaddress.Address,
 	chainID *hexutil.Uint,
 	networkId uint32,
-	gasPrice *hexutil.Big,
 	epochHeight *hexutil.Uint64,
 	err error,
 ) {
@@ -299,44 +324,36 @@ func (b *BulkSender) getChainInfos() (
 
 	_defaultAccount, err := _client.GetAccountManager().GetDefault()
 	if err != nil {
-		return nil, nil, 0, nil, nil, errors.Wrap(err, "failed to get default account")
+		return nil, nil, 0, nil, errors.Wrap(err, "failed to get default account")
 	}
 
 	bulkCaller := NewBulkCaller(_client)
 	_status, statusErr := bulkCaller.GetStatus()
-	_gasPrice, gasPriceErr := bulkCaller.GetGasPrice()
 	_epoch, epochErr := bulkCaller.GetEpochNumber(types.EpochLatestState)
 
 	err = bulkCaller.Execute()
 	if *statusErr != nil {
-		return nil, nil, 0, nil, nil, errors.Wrap(*statusErr, "failed to bulk fetch chain infos")
-	}
-	if *gasPriceErr != nil {
-		return nil, nil, 0, nil, nil, errors.Wrap(*gasPriceErr, "failed to bulk fetch chain infos")
+		return nil, nil, 0, nil, errors.Wrap(*statusErr, "failed to bulk fetch chain infos")
 	}
 	if *epochErr != nil {
-		return nil, nil, 0, nil, nil, errors.Wrap(*epochErr, "failed to bulk fetch chain infos")
+		return nil, nil, 0, nil, errors.Wrap(*epochErr, "failed to bulk fetch chain infos")
 	}
 	if err != nil {
-		return nil, nil, 0, nil, nil, errors.Wrap(err, "failed to bulk fetch chain infos")
+		return nil, nil, 0, nil, errors.Wrap(err, "failed to bulk fetch chain infos")
 	}
 
 	_chainID, _networkId := &_status.ChainID, uint32(_status.NetworkID)
 	_epochHeight := types.NewUint64(_epoch.ToInt().Uint64())
 
-	// conflux responsed gasprice offen be 0, but the min gasprice is 1 when sending transaction, so do this
-	if _gasPrice.ToInt().Cmp(big.NewInt(constants.MinGasprice)) < 1 {
-		_gasPrice = types.NewBigInt(constants.MinGasprice)
-	}
-
 	chainIDInUint := (hexutil.Uint)(*_chainID)
-	return _defaultAccount, &chainIDInUint, _networkId, _gasPrice, _epochHeight, nil
+	return _defaultAccount, &chainIDInUint, _networkId, _epochHeight, nil
 }
 
 // Clear clear batch elems and errors in queue for new bulk call action
 func (b *BulkSender) Clear() {
 	b.unsignedTxs = b.unsignedTxs[:0]
 	b.isPopulated = false
+	b.bulkEstimateErrors = nil
 }
 
 func (b *BulkSender) IsPopulated() bool {
@@ -355,14 +372,27 @@ func (b *BulkSender) SignAndSend() (txHashes []*types.Hash, txErrors []error, er
 		}
 	}
 
-	rawTxs := make([][]byte, len(b.unsignedTxs))
+	return b.signAndSend(b.unsignedTxs)
+}
+
+// signAndSend signs and sends the unsigned transactions by rpc call "batch" on one request, and records them
+// to outbox if set.
+func (b *BulkSender) signAndSend(unsignedTxs []*types.UnsignedTransaction) (txHashes []*types.Hash, txErrors []error, err error) {
+	outbox := b.outbox()
+	rawTxs := make([][]byte, len(unsignedTxs))
 
-	for i, utx := range b.unsignedTxs {
+	for i, utx := range unsignedTxs {
 		var err error
 		rawTxs[i], err = b.signableCaller.GetAccountManager().SignTransaction(*utx)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "failed to encode the %vth transaction: %+v", i, utx)
 		}
+
+		if outbox != nil {
+			if _, err := outbox.Record(*utx, rawTxs[i]); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to record the %vth transaction to outbox", i)
+			}
+		}
 	}
 
 	// send
@@ -381,6 +411,20 @@ func (b *BulkSender) SignAndSend() (txHashes []*types.Hash, txErrors []error, er
 	errorVals := make([]error, len(txErrs))
 	for i, err := range txErrs {
 		errorVals[i] = *err
+
+		// no hash responsed if the transaction already sent
+		if errorVals[i] != nil && sdk.IsTxAlreadyExistError(errorVals[i]) {
+			hash := sdk.RawTxHash(rawTxs[i])
+			hashes[i] = &hash
+		}
+	}
+
+	if outbox != nil {
+		for i, rawTx := range rawTxs {
+			if err := outbox.MarkSent(sdk.RawTxHash(rawTx), errorVals[i]); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to update the %vth transaction in outbox", i)
+			}
+		}
 	}
 
 	return hashes, errorVals, err
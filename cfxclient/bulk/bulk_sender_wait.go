@@ -0,0 +1,363 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/enums"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// SendStatus is the final status of transaction sent by SendAndWait.
+type SendStatus string
+
+const (
+	SendStatusExecuted        SendStatus = "executed"         // executed successfully
+	SendStatusExecutionFailed SendStatus = "execution_failed" // packed but failed to execute
+	SendStatusSkipped         SendStatus = "skipped"          // packed but skipped, e.g. not enough cash
+	SendStatusSendFailed      SendStatus = "send_failed"      // failed to populate or rejected by node
+	SendStatusTimeout         SendStatus = "timeout"          // sent but receipt not available before timeout
+)
+
+// SendAndWaitOption is the option of SendAndWait, and the zero fields are set to default values.
+type SendAndWaitOption struct {
+	NonceSource      types.NonceType // nonce source to populate transactions, see PopulateTransactions
+	MaxRetry         int             // max retries of transaction rejected for stale nonce or low gas price, default 3
+	GasPriceBumpRate int             // percentage to bump gas price when rejected for low gas price, default 10
+	Concurrency      int             // max number of transactions to wait receipts concurrently, default 10
+	PollInterval     time.Duration   // interval to poll receipt, default 1s
+	Timeout          time.Duration   // timeout to wait all receipts, default 5m
+}
+
+func (o *SendAndWaitOption) applyDefault() {
+	if o.MaxRetry == 0 {
+		o.MaxRetry = 3
+	}
+
+	if o.GasPriceBumpRate == 0 {
+		o.GasPriceBumpRate = 10
+	}
+
+	if o.Concurrency == 0 {
+		o.Concurrency = 10
+	}
+
+	if o.PollInterval == 0 {
+		o.PollInterval = time.Second
+	}
+
+	if o.Timeout == 0 {
+		o.Timeout = 5 * time.Minute
+	}
+}
+
+// SendResult is the result of a transaction in queue.
+type SendResult struct {
+	Index       int // index of transaction in queue
+	Transaction *types.UnsignedTransaction
+	Hash        *types.Hash
+	Retries     int
+	Status      SendStatus
+	Error       error // the send error, or the execution error of receipt
+	Receipt     *types.TransactionReceipt
+}
+
+// SendResults is the per-transaction results of SendAndWait in order of queue.
+type SendResults []*SendResult
+
+// Failed returns the results that not executed successfully.
+func (r SendResults) Failed() SendResults {
+	var failed SendResults
+	for _, v := range r {
+		if v.Status != SendStatusExecuted {
+			failed = append(failed, v)
+		}
+	}
+
+	return failed
+}
+
+// String formats the results as a table.
+func (r SendResults) String() string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tFROM\tNONCE\tHASH\tSTATUS\tRETRIES\tERROR")
+
+	for _, v := range r {
+		var from, nonce, hash, errMsg string
+		if v.Transaction.From != nil {
+			from = v.Transaction.From.String()
+		}
+
+		if v.Transaction.Nonce != nil {
+			nonce = v.Transaction.Nonce.ToInt().String()
+		}
+
+		if v.Hash != nil {
+			hash = v.Hash.String()
+		}
+
+		if v.Error != nil {
+			errMsg = v.Error.Error()
+		}
+
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", v.Index, from, nonce, hash, v.Status, v.Retries, errMsg)
+	}
+
+	w.Flush()
+
+	return sb.String()
+}
+
+// SendAndWait populates, signs and sends all unsigned transactions in queue, and waits for their receipts.
+//
+// Transactions of the same sender are sent one by one in nonce order, and transactions of different senders
+// are sent in one batch request, so that a failed transaction never leaves later nonces stuck in txpool:
+//   - a transaction rejected for stale nonce is retried with the next usable nonce, and the later nonces of the
+//     sender are re-sequenced
+//   - a transaction rejected for low gas price is retried with the gas price bumped by GasPriceBumpRate
+//   - a transaction failed to send after retries is given up, and the later nonces of the sender are
+//     re-sequenced to fill its nonce
+//
+// The receipts are waited with bounded concurrency until option.Timeout. The error is returned only if failed
+// to send the batch request, and the failures of transactions, including failed to get receipt, are reported
+// in results.
+func (b *BulkSender) SendAndWait(option ...SendAndWaitOption) (SendResults, error) {
+	var opt SendAndWaitOption
+	if len(option) > 0 {
+		opt = option[0]
+	}
+	opt.applyDefault()
+
+	results := make(SendResults, len(b.unsignedTxs))
+	for i, utx := range b.unsignedTxs {
+		results[i] = &SendResult{Index: i, Transaction: utx}
+	}
+
+	if !b.IsPopulated() {
+		if _, err := b.PopulateTransactions(opt.NonceSource); err != nil {
+			if _, ok := err.(*ErrBulkEstimate); !ok {
+				return nil, err
+			}
+		}
+	}
+
+	// transactions failed to estimate are not populated, e.g. nonce not set
+	if b.bulkEstimateErrors != nil {
+		for i, e := range *b.bulkEstimateErrors {
+			results[i].Status, results[i].Error = SendStatusSendFailed, e
+		}
+	}
+
+	if err := b.sendInNonceOrder(results, &opt); err != nil {
+		return nil, err
+	}
+
+	b.waitReceipts(results, &opt)
+
+	return results, nil
+}
+
+// sendInNonceOrder sends the head transactions of all senders in a batch per round until all sent or given up.
+func (b *BulkSender) sendInNonceOrder(results SendResults, opt *SendAndWaitOption) error {
+	queues := make(map[string][]*SendResult)
+	for _, v := range results {
+		if v.Status == "" {
+			from := v.Transaction.From.String()
+			queues[from] = append(queues[from], v)
+		}
+	}
+
+	for _, queue := range queues {
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].Transaction.Nonce.ToInt().Cmp(queue[j].Transaction.Nonce.ToInt()) < 0
+		})
+	}
+
+	for len(queues) > 0 {
+		var heads []*SendResult
+		for _, queue := range queues {
+			heads = append(heads, queue[0])
+		}
+
+		utxs := make([]*types.UnsignedTransaction, len(heads))
+		for i, v := range heads {
+			utxs[i] = v.Transaction
+		}
+
+		hashes, txErrs, err := b.signAndSend(utxs)
+		if err != nil {
+			return err
+		}
+
+		for i, head := range heads {
+			from := head.Transaction.From.String()
+			queue := queues[from]
+
+			sendErr := txErrs[i]
+			if sendErr == nil || sdk.IsTxAlreadyExistError(sendErr) {
+				head.Hash, head.Error = hashes[i], nil
+				queues[from] = queue[1:]
+			} else if head.Retries < opt.MaxRetry && isStaleNonceError(sendErr) {
+				head.Retries++
+
+				nonce, err := b.signableCaller.GetNextUsableNonce(*head.Transaction.From)
+				if err != nil {
+					return errors.WithMessagef(err, "failed to get next usable nonce of %v", from)
+				}
+
+				resequenceNonces(queue, nonce.ToInt())
+			} else if head.Retries < opt.MaxRetry && isLowGasPriceError(sendErr) {
+				head.Retries++
+				bumpGasPrice(head.Transaction, opt.GasPriceBumpRate)
+			} else {
+				head.Status, head.Error = SendStatusSendFailed, sendErr
+				queues[from] = queue[1:]
+
+				// fill the nonce of failed transaction with the later ones
+				resequenceNonces(queues[from], head.Transaction.Nonce.ToInt())
+			}
+
+			if len(queues[from]) == 0 {
+				delete(queues, from)
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitReceipts polls receipts of sent transactions with bounded concurrency until timeout. The error of getting
+// receipt is recorded in the result of that transaction, and the others are still waited.
+func (b *BulkSender) waitReceipts(results SendResults, opt *SendAndWaitOption) {
+	ctx, cancel := context.WithTimeout(context.Background(), opt.Timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opt.Concurrency)
+
+	for _, v := range results {
+		if v.Status != "" || v.Hash == nil {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(result *SendResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			b.waitReceipt(ctx, result, opt.PollInterval)
+		}(v)
+	}
+
+	wg.Wait()
+}
+
+// waitReceipt polls receipt of the sent transaction until available or timeout. The status is set to
+// SendStatusTimeout if failed to get receipt, along with the error.
+func (b *BulkSender) waitReceipt(ctx context.Context, result *SendResult, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := b.signableCaller.GetTransactionReceipt(*result.Hash)
+		if err != nil {
+			result.Status, result.Error = SendStatusTimeout, errors.WithMessagef(err, "failed to get receipt of %v", *result.Hash)
+			return
+		}
+
+		if receipt != nil {
+			result.Receipt = receipt
+			result.Status, result.Error = receiptStatus(receipt)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Status = SendStatusTimeout
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func receiptStatus(receipt *types.TransactionReceipt) (SendStatus, error) {
+	copied := *receipt
+	if copied.Space == nil {
+		space := types.SPACE_NATIVE
+		copied.Space = &space
+	}
+
+	outcome, err := copied.GetOutcomeType()
+	if err != nil {
+		return SendStatusExecutionFailed, err
+	}
+
+	var execErr error
+	if receipt.TxExecErrorMsg != nil {
+		execErr = errors.New(*receipt.TxExecErrorMsg)
+	}
+
+	switch outcome {
+	case enums.TRANSACTION_OUTCOME_SUCCESS:
+		return SendStatusExecuted, nil
+	case enums.TRANSACTION_OUTCOME_SKIPPED:
+		return SendStatusSkipped, execErr
+	default:
+		return SendStatusExecutionFailed, execErr
+	}
+}
+
+// resequenceNonces sets the nonces of transactions in queue continuously from the start nonce.
+func resequenceNonces(queue []*SendResult, start *big.Int) {
+	for i, v := range queue {
+		v.Transaction.Nonce = (*hexutil.Big)(new(big.Int).Add(start, big.NewInt(int64(i))))
+	}
+}
+
+// bumpGasPrice increases the gas price, or the max fee and priority fee of 1559 transaction, by the rate in percentage.
+func bumpGasPrice(utx *types.UnsignedTransaction, rate int) {
+	bump := func(value *hexutil.Big) *hexutil.Big {
+		if value == nil {
+			return nil
+		}
+
+		bumped := new(big.Int).Mul(value.ToInt(), big.NewInt(int64(100+rate)))
+		bumped.Div(bumped, big.NewInt(100))
+		// at least increase by 1 to replace the transaction with same nonce
+		if bumped.Cmp(value.ToInt()) <= 0 {
+			bumped.Add(value.ToInt(), big.NewInt(1))
+		}
+
+		return (*hexutil.Big)(bumped)
+	}
+
+	utx.GasPrice = bump(utx.GasPrice)
+	utx.MaxFeePerGas = bump(utx.MaxFeePerGas)
+	utx.MaxPriorityFeePerGas = bump(utx.MaxPriorityFeePerGas)
+}
+
+func isStaleNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "too stale nonce") || strings.Contains(msg, "nonce too low")
+}
+
+func isLowGasPriceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "same nonce already inserted") ||
+		(strings.Contains(msg, "gas price") && (strings.Contains(msg, "less than") || strings.Contains(msg, "too low")))
+}
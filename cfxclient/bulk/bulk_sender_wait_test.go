@@ -0,0 +1,250 @@
+package bulk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/internal/testutil"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendAndWait(t *testing.T) {
+	sent := make(map[uint64]uint64) // nonce => gas price of transactions accepted
+
+	provider := &testutil.FakeProvider{Handler: func(method string, args []interface{}) (interface{}, error) {
+		switch method {
+		case "cfx_getStatus":
+			return map[string]string{"chainId": "0x405", "networkId": "0x405", "ethereumSpaceChainId": "0x406"}, nil
+		case "cfx_epochNumber":
+			return "0x64", nil
+		case "cfx_gasPrice":
+			return "0x1", nil
+		case "cfx_getBlockByEpochNumber":
+			return nil, nil
+		case "txpool_nextNonce", "cfx_getNextNonce":
+			return "0x5", nil
+		case "cfx_getTransactionReceipt":
+			return map[string]string{"outcomeStatus": "0x0", "space": "native"}, nil
+		case "cfx_sendRawTransaction":
+			var tx types.SignedTransaction
+			if err := tx.Decode(hexutil.MustDecode(args[0].(string)), 1029); err != nil {
+				return nil, err
+			}
+
+			nonce, gasPrice := tx.UnsignedTransaction.Nonce.ToInt().Uint64(), tx.UnsignedTransaction.GasPrice.ToInt().Uint64()
+			switch {
+			case nonce < 5:
+				return nil, errors.New("Transaction is discarded due to a too stale nonce")
+			case nonce == 5 && gasPrice < 2:
+				return nil, errors.New("Tx with same nonce already inserted. To replace it, you need to specify a gas price > 1")
+			case nonce == 6 && sent[6] == 0 && tx.UnsignedTransaction.Value.ToInt().Uint64() == 100:
+				return nil, errors.New("insufficient balance")
+			}
+
+			sent[nonce] = gasPrice
+			hash, _ := tx.Hash()
+			return hexutil.Encode(hash), nil
+		}
+		return nil, errors.Errorf("unexpected method %v", method)
+	}}
+
+	client, err := sdk.NewClientWithProvider(provider)
+	assert.NoError(t, err)
+	client.SetNetworkId(1029)
+	client.SetChainId(1029)
+
+	am := sdk.NewPrivatekeyAccountManager([]string{"0x0123456789012345678901234567890123456789012345678901234567890123"}, 1029)
+	client.SetAccountManager(am)
+	from, err := am.GetDefault()
+	assert.NoError(t, err)
+
+	newTx := func(nonce, value uint64) *types.UnsignedTransaction {
+		tx := &types.UnsignedTransaction{To: from}
+		tx.From = from
+		tx.Nonce = types.NewBigInt(nonce)
+		tx.Value = types.NewBigInt(value)
+		tx.GasPrice = types.NewBigInt(1)
+		tx.Gas = types.NewBigInt(21000)
+		tx.StorageLimit = types.NewUint64(0)
+		return tx
+	}
+
+	bulkSender := NewBulkSender(*client)
+	bulkSender.AppendTransaction(newTx(0, 1)).AppendTransaction(newTx(1, 100)).AppendTransaction(newTx(2, 1))
+
+	// outbox set after bulk sender created is used too
+	outbox := sdk.NewOutboxWithStore(client, sdk.NewMemoryOutboxStore())
+	client.SetOutbox(outbox)
+
+	results, err := bulkSender.SendAndWait(SendAndWaitOption{
+		NonceSource:  types.NONCE_TYPE_NONCE,
+		PollInterval: time.Millisecond,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(results))
+
+	// stale nonce re-sequenced from 5, then gas price bumped
+	assert.Equal(t, SendStatusExecuted, results[0].Status)
+	assert.Equal(t, uint64(5), results[0].Transaction.Nonce.ToInt().Uint64())
+	assert.Equal(t, 2, results[0].Retries)
+	assert.Equal(t, uint64(2), sent[5])
+	assert.NotNil(t, results[0].Receipt)
+
+	assert.Equal(t, SendStatusSendFailed, results[1].Status)
+	assert.Contains(t, results[1].Error.Error(), "insufficient balance")
+
+	// re-sequenced to fill the nonce of failed transaction
+	assert.Equal(t, SendStatusExecuted, results[2].Status)
+	assert.Equal(t, uint64(6), results[2].Transaction.Nonce.ToInt().Uint64())
+	assert.Equal(t, 2, len(sent))
+
+	assert.Equal(t, 1, len(results.Failed()))
+
+	recorded, err := outbox.List(sdk.OutboxFilter{Statuses: []sdk.OutboxStatus{sdk.OutboxStatusSent}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(recorded))
+
+	lines := strings.Split(strings.TrimSpace(results.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "INDEX"))
+	assert.Contains(t, lines[2], "send_failed")
+}
+
+// respondChainQuery responds the queries of chain status used to populate transactions.
+func respondChainQuery(method string) (interface{}, bool) {
+	switch method {
+	case "cfx_getStatus":
+		return map[string]string{"chainId": "0x405", "networkId": "0x405", "ethereumSpaceChainId": "0x406"}, true
+	case "cfx_epochNumber":
+		return "0x64", true
+	case "cfx_gasPrice":
+		return "0x1", true
+	case "cfx_getBlockByEpochNumber":
+		return nil, true
+	case "txpool_nextNonce", "cfx_getNextNonce":
+		return "0x0", true
+	}
+	return nil, false
+}
+
+func newWaitTestClient(t *testing.T, provider *testutil.FakeProvider) (*sdk.Client, *cfxaddress.Address) {
+	client, err := sdk.NewClientWithProvider(provider)
+	assert.NoError(t, err)
+	client.SetNetworkId(1029)
+	client.SetChainId(1029)
+
+	am := sdk.NewPrivatekeyAccountManager([]string{"0x0123456789012345678901234567890123456789012345678901234567890123"}, 1029)
+	client.SetAccountManager(am)
+	from, err := am.GetDefault()
+	assert.NoError(t, err)
+
+	return client, from
+}
+
+func TestSendAndWaitReceiptError(t *testing.T) {
+	var failedHash string
+
+	provider := &testutil.FakeProvider{Handler: func(method string, args []interface{}) (interface{}, error) {
+		if value, ok := respondChainQuery(method); ok {
+			return value, nil
+		}
+
+		switch method {
+		case "cfx_getTransactionReceipt":
+			if fmt.Sprint(args[0]) == failedHash {
+				return nil, errors.New("connection reset")
+			}
+			return map[string]string{"outcomeStatus": "0x0", "space": "native"}, nil
+		case "cfx_sendRawTransaction":
+			var tx types.SignedTransaction
+			if err := tx.Decode(hexutil.MustDecode(args[0].(string)), 1029); err != nil {
+				return nil, err
+			}
+
+			hash, _ := tx.Hash()
+			if tx.UnsignedTransaction.Nonce.ToInt().Uint64() == 1 {
+				failedHash = hexutil.Encode(hash)
+			}
+			return hexutil.Encode(hash), nil
+		}
+		return nil, errors.Errorf("unexpected method %v", method)
+	}}
+
+	client, from := newWaitTestClient(t, provider)
+
+	bulkSender := NewBulkSender(*client)
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx := &types.UnsignedTransaction{To: from}
+		tx.From = from
+		tx.Nonce = types.NewBigInt(nonce)
+		tx.Value = types.NewBigInt(1)
+		tx.GasPrice = types.NewBigInt(1)
+		tx.Gas = types.NewBigInt(21000)
+		tx.StorageLimit = types.NewUint64(0)
+		bulkSender.AppendTransaction(tx)
+	}
+
+	results, err := bulkSender.SendAndWait(SendAndWaitOption{PollInterval: time.Millisecond})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(results))
+
+	assert.Equal(t, SendStatusExecuted, results[0].Status)
+	assert.Equal(t, SendStatusTimeout, results[1].Status)
+	assert.Contains(t, results[1].Error.Error(), "connection reset")
+	assert.Equal(t, SendStatusExecuted, results[2].Status)
+}
+
+func TestSendAndWaitPopulatedWithEstimateErrors(t *testing.T) {
+	provider := &testutil.FakeProvider{Handler: func(method string, args []interface{}) (interface{}, error) {
+		if value, ok := respondChainQuery(method); ok {
+			return value, nil
+		}
+
+		switch method {
+		case "cfx_estimateGasAndCollateral":
+			return nil, &utils.RpcError{Code: -32015, Message: "execution reverted"}
+		case "cfx_getTransactionReceipt":
+			return map[string]string{"outcomeStatus": "0x0", "space": "native"}, nil
+		case "cfx_sendRawTransaction":
+			var tx types.SignedTransaction
+			if err := tx.Decode(hexutil.MustDecode(args[0].(string)), 1029); err != nil {
+				return nil, err
+			}
+
+			hash, _ := tx.Hash()
+			return hexutil.Encode(hash), nil
+		}
+		return nil, errors.Errorf("unexpected method %v", method)
+	}}
+
+	client, from := newWaitTestClient(t, provider)
+
+	estimated := &types.UnsignedTransaction{To: from}
+	estimated.From = from
+	estimated.Gas = types.NewBigInt(21000)
+	estimated.StorageLimit = types.NewUint64(0)
+
+	reverted := &types.UnsignedTransaction{To: from}
+	reverted.From = from
+
+	bulkSender := NewBulkSender(*client)
+	bulkSender.AppendTransaction(reverted).AppendTransaction(estimated)
+
+	_, err := bulkSender.PopulateTransactions(types.NONCE_TYPE_NONCE)
+	assert.IsType(t, &ErrBulkEstimate{}, err)
+
+	results, err := bulkSender.SendAndWait(SendAndWaitOption{PollInterval: time.Millisecond})
+	assert.NoError(t, err)
+
+	assert.Equal(t, SendStatusSendFailed, results[0].Status)
+	assert.Contains(t, results[0].Error.Error(), "execution reverted")
+	assert.Equal(t, SendStatusExecuted, results[1].Status)
+}
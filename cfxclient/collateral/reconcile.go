@@ -0,0 +1,170 @@
+package collateral
+
+import (
+	"math/big"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// Mismatch is the storage collateral change of address that does not reconcile with the tracker.
+type Mismatch struct {
+	Address          *types.Address // nil for the total storage collateral of network
+	TrackedChange    *big.Int       // net collateral change in tracker
+	CollateralChange *big.Int       // collateral delta returned by node
+	UntrackedChange  *big.Int       // CollateralChange - TrackedChange
+}
+
+// Depletion is the prediction of when the sponsor balance for collateral of contract runs out.
+type Depletion struct {
+	Contract      types.Address
+	Available     *big.Int // sponsor balance for collateral and the available storage points
+	RatePerEpoch  *big.Int // average collateral consumed by the contract per epoch in tracked epochs
+	EpochsLeft    uint64   // epochs left before running out, only valid if not Never
+	DepletedEpoch uint64   // the epoch predicted to run out, only valid if not Never
+	Never         bool     // true if the sponsored storage of contract is not increasing
+}
+
+// Reconcile compares the storage collateral change of addresses in tracker with the collateral delta returned by
+// GetCollateralForStorage between the epoch before the tracker started and the last tracked epoch, and returns the
+// mismatched addresses. If no address specified, all owners tracked will be reconciled.
+func Reconcile(client sdk.ClientOperator, tracker *Tracker, addresses ...types.Address) ([]Mismatch, error) {
+	before, after, err := reconcileEpochs(tracker)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addresses) == 0 {
+		addresses = tracker.Owners()
+	}
+
+	var mismatches []Mismatch
+
+	for i := range addresses {
+		address := addresses[i]
+
+		collateralBefore, err := client.GetCollateralForStorage(address, types.NewEpochNumberUint64(before))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get collateral of %v at epoch %v", address, before)
+		}
+
+		collateralAfter, err := client.GetCollateralForStorage(address, types.NewEpochNumberUint64(after))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get collateral of %v at epoch %v", address, after)
+		}
+
+		change := new(big.Int).Sub(collateralAfter.ToInt(), collateralBefore.ToInt())
+		if mismatch := newMismatch(&address, tracker.OwnerCollateral(address), change); mismatch != nil {
+			mismatches = append(mismatches, *mismatch)
+		}
+	}
+
+	return mismatches, nil
+}
+
+// ReconcileTotal compares the total storage collateral change in tracker with the delta of the total storage
+// tokens and used storage points returned by GetCollateralInfo, and returns nil if reconciled.
+func ReconcileTotal(client sdk.ClientOperator, tracker *Tracker) (*Mismatch, error) {
+	before, after, err := reconcileEpochs(tracker)
+	if err != nil {
+		return nil, err
+	}
+
+	infoBefore, err := client.GetCollateralInfo(types.NewEpochNumberUint64(before))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get collateral info at epoch %v", before)
+	}
+
+	infoAfter, err := client.GetCollateralInfo(types.NewEpochNumberUint64(after))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get collateral info at epoch %v", after)
+	}
+
+	change := new(big.Int).Sub(totalCollateral(infoAfter), totalCollateral(infoBefore))
+
+	return newMismatch(nil, bytesToCollateral(tracker.TotalBytes()), change), nil
+}
+
+// PredictDepletion predicts when the sponsor balance for collateral of contract runs out, according to the
+// average sponsored storage collateral consumed by the contract per epoch in tracked epochs.
+func PredictDepletion(client sdk.ClientOperator, tracker *Tracker, contract types.Address) (*Depletion, error) {
+	lastEpoch, ok := tracker.LastEpoch()
+	if !ok {
+		return nil, errors.New("no epoch tracked")
+	}
+
+	sponsor, err := client.GetSponsorInfo(contract, types.NewEpochNumberUint64(lastEpoch))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get sponsor info of %v", contract)
+	}
+
+	depletion := Depletion{
+		Contract:     contract,
+		Available:    new(big.Int),
+		RatePerEpoch: new(big.Int),
+	}
+
+	if sponsor.SponsorBalanceForCollateral != nil {
+		depletion.Available.Add(depletion.Available, sponsor.SponsorBalanceForCollateral.ToInt())
+	}
+
+	if sponsor.AvailableStoragePoints != nil {
+		depletion.Available.Add(depletion.Available, sponsor.AvailableStoragePoints.ToInt())
+	}
+
+	// the sponsored collateral is locked for the contract itself
+	consumed := tracker.OwnerCollateral(contract)
+	epochs := big.NewInt(int64(lastEpoch - tracker.StartEpoch() + 1))
+	depletion.RatePerEpoch.Div(consumed, epochs)
+
+	if depletion.RatePerEpoch.Sign() <= 0 {
+		depletion.Never = true
+		return &depletion, nil
+	}
+
+	depletion.EpochsLeft = new(big.Int).Div(depletion.Available, depletion.RatePerEpoch).Uint64()
+	depletion.DepletedEpoch = lastEpoch + depletion.EpochsLeft
+
+	return &depletion, nil
+}
+
+func reconcileEpochs(tracker *Tracker) (before, after uint64, err error) {
+	if tracker.StartEpoch() == 0 {
+		return 0, 0, errors.New("tracker started from genesis epoch is not supported")
+	}
+
+	lastEpoch, ok := tracker.LastEpoch()
+	if !ok {
+		return 0, 0, errors.New("no epoch tracked")
+	}
+
+	return tracker.StartEpoch() - 1, lastEpoch, nil
+}
+
+func newMismatch(address *types.Address, tracked, change *big.Int) *Mismatch {
+	if tracked.Cmp(change) == 0 {
+		return nil
+	}
+
+	return &Mismatch{
+		Address:          address,
+		TrackedChange:    tracked,
+		CollateralChange: change,
+		UntrackedChange:  new(big.Int).Sub(change, tracked),
+	}
+}
+
+func totalCollateral(info types.StorageCollateralInfo) *big.Int {
+	total := new(big.Int)
+
+	if info.TotalStorageTokens != nil {
+		total.Add(total, info.TotalStorageTokens.ToInt())
+	}
+
+	if info.UsedStoragePoints != nil {
+		total.Add(total, info.UsedStoragePoints.ToInt())
+	}
+
+	return total
+}
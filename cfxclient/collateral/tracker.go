@@ -0,0 +1,269 @@
+// Package collateral tracks the storage collateral owed by addresses and occupied by contracts from receipts,
+// which helps sponsors to predict when the sponsor balance for collateral will run out.
+package collateral
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/light/primitives"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Change is a storage collateral change of transaction.
+type Change struct {
+	EpochNumber     uint64
+	TransactionHash types.Hash
+	Owner           types.Address  // the address that collateral locked for or refunded to
+	Contract        *types.Address // the called or created contract of transaction, nil for simple transfer
+	Bytes           int64          // positive for collateralized and negative for released
+	Sponsored       bool           // true if collateral paid by the sponsor of contract
+}
+
+// Collateral returns the collateral of change in drip, which is negative for released.
+func (c *Change) Collateral() *big.Int {
+	return bytesToCollateral(c.Bytes)
+}
+
+// Point is the net storage change of address since the tracker started at an epoch.
+type Point struct {
+	EpochNumber uint64
+	Bytes       int64
+}
+
+// Tracker tracks the storage collateral changes from the core space receipts of continuous epochs.
+type Tracker struct {
+	mu         sync.RWMutex
+	startEpoch uint64
+	nextEpoch  uint64
+	networkID  uint32
+
+	owners    map[common.Address]int64 // net bytes collateralized per owner
+	contracts map[common.Address]int64 // net bytes collateralized per contract
+	changes   []Change
+}
+
+// NewTracker creates a tracker which starts to consume receipts from the specified epoch.
+func NewTracker(startEpoch uint64) *Tracker {
+	return &Tracker{
+		startEpoch: startEpoch,
+		nextEpoch:  startEpoch,
+		owners:     make(map[common.Address]int64),
+		contracts:  make(map[common.Address]int64),
+	}
+}
+
+// StartEpoch returns the first epoch tracked.
+func (t *Tracker) StartEpoch() uint64 {
+	return t.startEpoch
+}
+
+// LastEpoch returns the last epoch tracked, and false if no epoch tracked yet.
+func (t *Tracker) LastEpoch() (uint64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.nextEpoch - 1, t.nextEpoch > t.startEpoch
+}
+
+// AddEpoch consumes the receipts of epoch, e.g. returned by GetEpochReceipts, and returns the storage
+// collateral changes in epoch. Epochs must be added in order without gap. Receipts of eSpace are ignored
+// since storage collateral only applies to core space.
+func (t *Tracker) AddEpoch(epochNumber uint64, receipts [][]types.TransactionReceipt) ([]Change, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if epochNumber != t.nextEpoch {
+		return nil, errors.Errorf("epoch %v is not continuous, expected %v", epochNumber, t.nextEpoch)
+	}
+
+	var changes []Change
+
+	for _, blockReceipts := range receipts {
+		for i := range blockReceipts {
+			receipt := &blockReceipts[i]
+			if receipt.Space != nil && *receipt.Space != types.SPACE_NATIVE {
+				continue
+			}
+
+			changes = append(changes, t.changesOf(epochNumber, receipt)...)
+		}
+	}
+
+	for _, v := range changes {
+		owner := v.Owner.MustGetCommonAddress()
+		t.owners[owner] += v.Bytes
+
+		if v.Contract != nil {
+			t.contracts[v.Contract.MustGetCommonAddress()] += v.Bytes
+		}
+	}
+
+	t.changes = append(t.changes, changes...)
+	t.nextEpoch++
+
+	return changes, nil
+}
+
+func (t *Tracker) changesOf(epochNumber uint64, receipt *types.TransactionReceipt) []Change {
+	if receipt.StorageCollateralized == 0 && len(receipt.StorageReleased) == 0 {
+		return nil
+	}
+
+	networkID := receipt.From.GetNetworkID()
+	if t.networkID == 0 {
+		t.networkID = networkID
+	}
+
+	contract := receipt.To
+	if receipt.ContractCreated != nil {
+		contract = receipt.ContractCreated
+	}
+
+	if contract != nil && contract.GetAddressType() != cfxaddress.AddressTypeContract {
+		contract = nil
+	}
+
+	collateralized, released := primitives.ConstructStorageChanges(receipt)
+
+	var changes []Change
+
+	for _, v := range collateralized {
+		changes = append(changes, Change{
+			EpochNumber:     epochNumber,
+			TransactionHash: receipt.TransactionHash,
+			Owner:           cfxaddress.MustNewFromCommon(v.Account, networkID),
+			Contract:        contract,
+			Bytes:           int64(v.Collaterals),
+			Sponsored:       receipt.StorageCoveredBySponsor,
+		})
+	}
+
+	for _, v := range released {
+		owner := cfxaddress.MustNewFromCommon(v.Account, networkID)
+
+		changes = append(changes, Change{
+			EpochNumber:     epochNumber,
+			TransactionHash: receipt.TransactionHash,
+			Owner:           owner,
+			Contract:        contract,
+			Bytes:           -int64(v.Collaterals),
+			// released to the contract itself if its storage was sponsored
+			Sponsored: owner.GetAddressType() == cfxaddress.AddressTypeContract,
+		})
+	}
+
+	return changes
+}
+
+// Changes returns all the storage collateral changes tracked.
+func (t *Tracker) Changes() []Change {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return append([]Change(nil), t.changes...)
+}
+
+// Releases returns the storage collateral releases since the specified epoch.
+func (t *Tracker) Releases(fromEpoch uint64) []Change {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var releases []Change
+	for _, v := range t.changes {
+		if v.Bytes < 0 && v.EpochNumber >= fromEpoch {
+			releases = append(releases, v)
+		}
+	}
+
+	return releases
+}
+
+// OwnerBytes returns the net storage collateralized by owner in bytes since the tracker started.
+func (t *Tracker) OwnerBytes(owner types.Address) int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.owners[owner.MustGetCommonAddress()]
+}
+
+// OwnerCollateral returns the net storage collateral locked for owner in drip since the tracker started.
+func (t *Tracker) OwnerCollateral(owner types.Address) *big.Int {
+	return bytesToCollateral(t.OwnerBytes(owner))
+}
+
+// ContractBytes returns the net storage collateralized by transactions to contract in bytes since the tracker started.
+func (t *Tracker) ContractBytes(contract types.Address) int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.contracts[contract.MustGetCommonAddress()]
+}
+
+// History returns the net storage change of owner since the tracker started at each epoch that changed.
+func (t *Tracker) History(owner types.Address) []Point {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var (
+		points []Point
+		bytes  int64
+	)
+
+	target := owner.MustGetCommonAddress()
+
+	for _, v := range t.changes {
+		if v.Owner.MustGetCommonAddress() != target {
+			continue
+		}
+
+		bytes += v.Bytes
+
+		if n := len(points); n > 0 && points[n-1].EpochNumber == v.EpochNumber {
+			points[n-1].Bytes = bytes
+		} else {
+			points = append(points, Point{v.EpochNumber, bytes})
+		}
+	}
+
+	return points
+}
+
+// Owners returns all the owners of which the storage collateral changed, sorted by base32 address.
+func (t *Tracker) Owners() []types.Address {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	owners := make([]types.Address, 0, len(t.owners))
+	for k := range t.owners {
+		owners = append(owners, cfxaddress.MustNewFromCommon(k, t.networkID))
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		return owners[i].String() < owners[j].String()
+	})
+
+	return owners
+}
+
+// TotalBytes returns the net storage collateralized of all owners in bytes since the tracker started.
+func (t *Tracker) TotalBytes() int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var total int64
+	for _, v := range t.owners {
+		total += v
+	}
+
+	return total
+}
+
+func bytesToCollateral(bytes int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(bytes), constants.CollateralPerStorageByte)
+}
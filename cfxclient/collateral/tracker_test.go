@@ -0,0 +1,148 @@
+package collateral
+
+import (
+	"math/big"
+	"testing"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	user     = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000001", 1029)
+	contract = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000002", 1029)
+)
+
+type fakeClient struct {
+	sdk.ClientOperator
+	collaterals map[uint64]map[string]int64 // epoch => address => bytes
+}
+
+func (c *fakeClient) GetCollateralForStorage(account types.Address, epoch ...*types.Epoch) (*hexutil.Big, error) {
+	number, _ := epoch[0].ToInt()
+	return (*hexutil.Big)(bytesToCollateral(c.collaterals[number.Uint64()][account.String()])), nil
+}
+
+func (c *fakeClient) GetCollateralInfo(epoch ...*types.Epoch) (types.StorageCollateralInfo, error) {
+	number, _ := epoch[0].ToInt()
+
+	var total int64
+	for _, v := range c.collaterals[number.Uint64()] {
+		total += v
+	}
+
+	return types.StorageCollateralInfo{TotalStorageTokens: (*hexutil.Big)(bytesToCollateral(total))}, nil
+}
+
+func (c *fakeClient) GetSponsorInfo(contractAddress types.Address, epoch ...*types.Epoch) (types.SponsorInfo, error) {
+	// collateral of 1024 bytes left
+	return types.SponsorInfo{
+		SponsorBalanceForCollateral: (*hexutil.Big)(bytesToCollateral(1024)),
+	}, nil
+}
+
+func newReceipt(sponsored bool, collateralized uint64, released ...types.StorageChange) types.TransactionReceipt {
+	space := types.SPACE_NATIVE
+
+	return types.TransactionReceipt{
+		From:                    user,
+		To:                      &contract,
+		StorageCoveredBySponsor: sponsored,
+		StorageCollateralized:   hexutil.Uint64(collateralized),
+		StorageReleased:         released,
+		Space:                   &space,
+	}
+}
+
+func newTestTracker(t *testing.T) *Tracker {
+	tracker := NewTracker(10)
+
+	changes, err := tracker.AddEpoch(10, [][]types.TransactionReceipt{{
+		newReceipt(false, 128),
+		newReceipt(true, 64),
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(changes))
+
+	_, err = tracker.AddEpoch(12, nil)
+	assert.Error(t, err)
+
+	changes, err = tracker.AddEpoch(11, [][]types.TransactionReceipt{{
+		newReceipt(true, 128, types.StorageChange{Address: user, Collaterals: 64}),
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(changes))
+
+	return tracker
+}
+
+func TestTracker(t *testing.T) {
+	tracker := newTestTracker(t)
+
+	assert.Equal(t, int64(64), tracker.OwnerBytes(user))
+	assert.Equal(t, int64(192), tracker.OwnerBytes(contract))
+	assert.Equal(t, int64(256), tracker.ContractBytes(contract))
+	assert.Equal(t, int64(256), tracker.TotalBytes())
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(64), constants.CollateralPerStorageByte), tracker.OwnerCollateral(user))
+
+	assert.Equal(t, []Point{{10, 128}, {11, 64}}, tracker.History(user))
+	assert.Equal(t, []types.Address{user, contract}, tracker.Owners())
+
+	releases := tracker.Releases(11)
+	assert.Equal(t, 1, len(releases))
+	assert.Equal(t, int64(-64), releases[0].Bytes)
+	assert.False(t, releases[0].Sponsored)
+	assert.Empty(t, tracker.Releases(12))
+
+	lastEpoch, ok := tracker.LastEpoch()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(11), lastEpoch)
+}
+
+func TestReconcile(t *testing.T) {
+	tracker := newTestTracker(t)
+
+	client := &fakeClient{collaterals: map[uint64]map[string]int64{
+		9:  {user.String(): 1000, contract.String(): 0},
+		11: {user.String(): 1064, contract.String(): 200},
+	}}
+
+	mismatches, err := Reconcile(client, tracker)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mismatches))
+	assert.Equal(t, contract.String(), mismatches[0].Address.String())
+	assert.Equal(t, bytesToCollateral(8), mismatches[0].UntrackedChange)
+
+	total, err := ReconcileTotal(client, tracker)
+	assert.NoError(t, err)
+	assert.Equal(t, bytesToCollateral(8), total.UntrackedChange)
+
+	client.collaterals[11][contract.String()] = 192
+	total, err = ReconcileTotal(client, tracker)
+	assert.NoError(t, err)
+	assert.Nil(t, total)
+}
+
+func TestPredictDepletion(t *testing.T) {
+	tracker := newTestTracker(t)
+
+	// 192 bytes sponsored in 2 epochs
+	depletion, err := PredictDepletion(&fakeClient{}, tracker, contract)
+	assert.NoError(t, err)
+	assert.False(t, depletion.Never)
+	assert.Equal(t, bytesToCollateral(96), depletion.RatePerEpoch)
+	assert.Equal(t, uint64(10), depletion.EpochsLeft)
+	assert.Equal(t, uint64(21), depletion.DepletedEpoch)
+
+	tracker = NewTracker(10)
+	_, err = tracker.AddEpoch(10, nil)
+	assert.NoError(t, err)
+
+	depletion, err = PredictDepletion(&fakeClient{}, tracker, contract)
+	assert.NoError(t, err)
+	assert.True(t, depletion.Never)
+}
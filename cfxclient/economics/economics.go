@@ -0,0 +1,199 @@
+// Package economics computes the staking yields of Conflux network with the same formulas as the protocol,
+// including the PoW staking interest, PoS APR and projected rewards for a stake amount and lock duration.
+package economics
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	internalcontract "github.com/Conflux-Chain/go-conflux-sdk/contract_meta/internal_contract"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlocksPerYear is the expected number of blocks generated in a year, which is used by the protocol to compute interest
+	BlocksPerYear = internalcontract.BlocksPerYear
+	// BlocksPerSecond is the expected number of blocks generated per second
+	BlocksPerSecond = internalcontract.BlocksPerSecond
+)
+
+// Snapshot is the economics state of Conflux network at an epoch.
+type Snapshot struct {
+	BlockNumber              uint64   // number of the pivot block of epoch
+	InterestRate             *big.Int // annualized interest rate scaled by internalcontract.InterestRatePerBlockScale
+	TotalCirculating         *big.Int
+	TotalStaking             *big.Int
+	TotalPosStaking          *big.Int
+	DistributablePosInterest *big.Int // PoS interest accumulated since LastDistributeBlock
+	LastDistributeBlock      uint64
+}
+
+// NewSnapshot queries the economics state of Conflux network at the specified epoch, or latest state if not specified.
+func NewSnapshot(client sdk.ClientOperator, epoch ...*types.Epoch) (*Snapshot, error) {
+	target := types.EpochLatestState
+	if len(epoch) > 0 && epoch[0] != nil {
+		target = epoch[0]
+	}
+
+	epochNumber, err := client.GetEpochNumber(target)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get epoch number")
+	}
+
+	// pin the epoch so that all the states are read from the same epoch
+	epoch = []*types.Epoch{types.NewEpochNumber(epochNumber)}
+
+	pivotBlock, err := client.GetBlockSummaryByEpoch(epoch[0])
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get pivot block")
+	}
+
+	if pivotBlock == nil || pivotBlock.BlockNumber == nil {
+		return nil, errors.Errorf("block number of pivot block in epoch %v not available", epochNumber)
+	}
+
+	rate, err := client.GetInterestRate(epoch...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get interest rate")
+	}
+
+	supply, err := client.GetSupplyInfo(epoch...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get supply info")
+	}
+
+	posEconomics, err := client.GetPoSEconomics(epoch...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get PoS economics")
+	}
+
+	if rate == nil || supply.TotalCirculating == nil || supply.TotalStaking == nil || posEconomics.TotalPosStakingTokens == nil {
+		return nil, errors.New("economics state not available")
+	}
+
+	snapshot := Snapshot{
+		BlockNumber:              pivotBlock.BlockNumber.ToInt().Uint64(),
+		InterestRate:             rate.ToInt(),
+		TotalCirculating:         supply.TotalCirculating.ToInt(),
+		TotalStaking:             supply.TotalStaking.ToInt(),
+		TotalPosStaking:          posEconomics.TotalPosStakingTokens.ToInt(),
+		DistributablePosInterest: new(big.Int),
+		LastDistributeBlock:      uint64(posEconomics.LastDistributeBlock),
+	}
+
+	if posEconomics.DistributablePosInterest != nil {
+		snapshot.DistributablePosInterest = posEconomics.DistributablePosInterest.ToInt()
+	}
+
+	return &snapshot, nil
+}
+
+// AnnualRate converts the scaled interest rate to float, e.g. 0.04 for 4%.
+func AnnualRate(scaledRate *big.Int) float64 {
+	rate, _ := new(big.Rat).SetFrac(scaledRate, internalcontract.InterestRatePerBlockScale).Float64()
+	return rate
+}
+
+// PowStakingAPR returns the annual interest rate of PoW staking without compounding.
+func (s *Snapshot) PowStakingAPR() float64 {
+	return AnnualRate(s.InterestRate)
+}
+
+// PowStakingAPY returns the annual yield of PoW staking, of which the interest is compounded per block.
+func (s *Snapshot) PowStakingAPY() float64 {
+	return compound(s.PowStakingAPR()/BlocksPerYear, BlocksPerYear)
+}
+
+// PowInterestPerBlock returns the PoW staking interest per block for all staked tokens.
+func (s *Snapshot) PowInterestPerBlock() *big.Int {
+	interest := new(big.Int).Mul(s.TotalStaking, s.interestRatePerBlock())
+	return interest.Div(interest, internalcontract.InterestRatePerBlockScale)
+}
+
+// PosInterestPerBlock returns the PoS interest accumulated to distribute per block, which equals to
+// sqrt(totalCirculating * totalPosStaking) * interestRatePerBlock.
+func (s *Snapshot) PosInterestPerBlock() *big.Int {
+	interest := new(big.Int).Mul(s.TotalCirculating, s.TotalPosStaking)
+	interest.Sqrt(interest)
+	interest.Mul(interest, s.interestRatePerBlock())
+	return interest.Div(interest, internalcontract.InterestRatePerBlockScale)
+}
+
+// PosAPR returns the annual interest rate of PoS staking, namely interestRate * sqrt(totalCirculating / totalPosStaking).
+//
+// Note, PoS rewards are distributed to PoS accounts periodically instead of compounded automatically, and only
+// the votes of committee members are rewarded, so the actual yield of a PoS account depends on the election.
+func (s *Snapshot) PosAPR() float64 {
+	if s.TotalPosStaking.Sign() == 0 {
+		return 0
+	}
+
+	ratio, _ := new(big.Rat).SetFrac(s.TotalCirculating, s.TotalPosStaking).Float64()
+
+	return s.PowStakingAPR() * math.Sqrt(ratio)
+}
+
+// PosDistributableAPR returns the annual interest rate of PoS staking observed from the distributable PoS interest,
+// which is accumulated since LastDistributeBlock and annualized over the total PoS staking.
+//
+// Different from PosAPR which is derived from the current interest rate, it reflects the interest actually
+// accumulated in the current distribution period. Like PosAPR, the rewards are not compounded automatically.
+// Returns 0 if no block elapsed or no PoS staking.
+func (s *Snapshot) PosDistributableAPR() float64 {
+	if s.BlockNumber <= s.LastDistributeBlock || s.TotalPosStaking.Sign() == 0 || s.DistributablePosInterest == nil {
+		return 0
+	}
+
+	blocks := new(big.Int).SetUint64(s.BlockNumber - s.LastDistributeBlock)
+
+	annual := new(big.Int).Mul(s.DistributablePosInterest, big.NewInt(BlocksPerYear))
+	rate, _ := new(big.Rat).SetFrac(annual, blocks.Mul(blocks, s.TotalPosStaking)).Float64()
+
+	return rate
+}
+
+// PosAnnualInterest returns the total PoS interest to distribute in a year.
+func (s *Snapshot) PosAnnualInterest() *big.Int {
+	return new(big.Int).Mul(s.PosInterestPerBlock(), big.NewInt(BlocksPerYear))
+}
+
+// ProjectPowStakingReward returns the projected PoW staking interest for the stake amount and lock duration,
+// assuming the interest rate does not change.
+func (s *Snapshot) ProjectPowStakingReward(amount *big.Int, duration time.Duration) *big.Int {
+	blocks := durationToBlocks(duration)
+	rate := compound(s.PowStakingAPR()/BlocksPerYear, blocks)
+	return mulFloat(amount, rate)
+}
+
+// ProjectPosReward returns the projected PoS reward for the stake amount and lock duration without compounding,
+// assuming the total PoS staking, total circulating tokens and interest rate do not change.
+func (s *Snapshot) ProjectPosReward(amount *big.Int, duration time.Duration) *big.Int {
+	years := float64(durationToBlocks(duration)) / BlocksPerYear
+	return mulFloat(amount, s.PosAPR()*years)
+}
+
+// interestRatePerBlock returns the interest rate per block scaled by internalcontract.InterestRatePerBlockScale.
+func (s *Snapshot) interestRatePerBlock() *big.Int {
+	return new(big.Int).Div(s.InterestRate, big.NewInt(BlocksPerYear))
+}
+
+// compound returns the yield of rate compounded by n times, namely (1 + rate)^n - 1.
+func compound(rate float64, n uint64) float64 {
+	return math.Expm1(float64(n) * math.Log1p(rate))
+}
+
+func durationToBlocks(duration time.Duration) uint64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return uint64(duration.Seconds() * BlocksPerSecond)
+}
+
+func mulFloat(amount *big.Int, factor float64) *big.Int {
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(factor)).Int(nil)
+	return result
+}
@@ -0,0 +1,77 @@
+package economics
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const year = 365 * 24 * time.Hour
+
+func cfx(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+func toCFX(drip *big.Int) float64 {
+	value, _ := new(big.Rat).SetFrac(drip, big.NewInt(1e18)).Float64()
+	return value
+}
+
+func newTestSnapshot() *Snapshot {
+	return &Snapshot{
+		InterestRate:     big.NewInt(40_000 * BlocksPerYear), // 4%
+		TotalCirculating: cfx(100_000),
+		TotalStaking:     cfx(50_000),
+		TotalPosStaking:  cfx(25_000),
+	}
+}
+
+func TestAnnualRate(t *testing.T) {
+	assert.Equal(t, 0.04, AnnualRate(big.NewInt(40_000*BlocksPerYear)))
+	assert.Equal(t, 0.0, AnnualRate(big.NewInt(0)))
+}
+
+func TestPowStaking(t *testing.T) {
+	s := newTestSnapshot()
+
+	assert.Equal(t, 0.04, s.PowStakingAPR())
+	assert.InDelta(t, 0.0408108, s.PowStakingAPY(), 1e-7)
+
+	// 50,000 CFX * 40,000 / (BlocksPerYear * 1e6)
+	assert.Equal(t, "31709791983764", s.PowInterestPerBlock().String())
+
+	assert.InDelta(t, 40.8108, toCFX(s.ProjectPowStakingReward(cfx(1000), year)), 1e-4)
+	assert.Equal(t, 0, s.ProjectPowStakingReward(cfx(1000), 0).Sign())
+}
+
+func TestPosStaking(t *testing.T) {
+	s := newTestSnapshot()
+
+	// 4% * sqrt(100,000 / 25,000)
+	assert.InDelta(t, 0.08, s.PosAPR(), 1e-12)
+
+	// sqrt(100,000 CFX * 25,000 CFX) * 40,000 / (BlocksPerYear * 1e6)
+	assert.Equal(t, "31709791983764", s.PosInterestPerBlock().String())
+	assert.InDelta(t, 2000, toCFX(s.PosAnnualInterest()), 1e-6)
+
+	assert.InDelta(t, 80, toCFX(s.ProjectPosReward(cfx(1000), year)), 1e-9)
+	assert.InDelta(t, 40, toCFX(s.ProjectPosReward(cfx(1000), year/2)), 1e-9)
+
+	s.TotalPosStaking = new(big.Int)
+	assert.Equal(t, 0.0, s.PosAPR())
+}
+
+func TestPosDistributableAPR(t *testing.T) {
+	s := newTestSnapshot()
+	assert.Equal(t, 0.0, s.PosDistributableAPR())
+
+	// 2,000 CFX per year for 25,000 CFX staked, accumulated in an hour
+	s.BlockNumber, s.LastDistributeBlock = 107200, 100000
+	s.DistributablePosInterest = new(big.Int).Div(new(big.Int).Mul(cfx(2000), big.NewInt(7200)), big.NewInt(BlocksPerYear))
+	assert.InDelta(t, 0.08, s.PosDistributableAPR(), 1e-12)
+
+	s.TotalPosStaking = new(big.Int)
+	assert.Equal(t, 0.0, s.PosDistributableAPR())
+}
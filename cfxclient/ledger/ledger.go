@@ -0,0 +1,276 @@
+// Package ledger turns the traces of an epoch into balanced debit and credit entries of CFX value flows,
+// e.g. top level and internal transfers, gas payment, storage collateral and staking, which is useful for
+// exchanges to credit deposits.
+package ledger
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/enums"
+	"github.com/pkg/errors"
+)
+
+// Reason is the reason of CFX value flow
+type Reason string
+
+const (
+	ReasonTransfer                  Reason = "transfer"          // value of top level call or create
+	ReasonInternalTransfer          Reason = "internal_transfer" // value of internal call or create
+	ReasonGasPayment                Reason = "gas_payment"
+	ReasonGasSponsored              Reason = "gas_sponsored"
+	ReasonGasRefund                 Reason = "gas_refund"
+	ReasonStorageCollateralLocked   Reason = "storage_collateral_locked"
+	ReasonStorageCollateralReleased Reason = "storage_collateral_released"
+	ReasonStakingDeposit            Reason = "staking_deposit"
+	ReasonStakingWithdraw           Reason = "staking_withdraw"
+	ReasonSponsorDeposit            Reason = "sponsor_deposit"
+	ReasonSponsorRefund             Reason = "sponsor_refund"
+	ReasonMint                      Reason = "mint"
+	ReasonBurn                      Reason = "burn"
+	ReasonOther                     Reason = "other"
+)
+
+// Account is a pocket of address in specified space, e.g. balance, staking balance or storage collateral.
+type Account struct {
+	Address string // base32 address
+	Space   types.SpaceType
+	Pocket  types.PocketType
+}
+
+// Entry is a debit or credit of account. Entries are generated in pairs for each value flow, and the
+// amounts of a pair sum to zero.
+type Entry struct {
+	TransactionHash *types.Hash
+	TraceIndex      int // index of trace in epoch
+	Account         Account
+	Amount          *big.Int // positive for credit and negative for debit
+	Reason          Reason
+}
+
+// Ledger contains the CFX value flow entries of an epoch.
+type Ledger struct {
+	EpochNumber uint64
+	Entries     []Entry
+}
+
+// Build builds the ledger of epoch with the core space traces and receipts of epoch, e.g. returned by
+// Trace().GetEpochTraces and GetEpochReceipts. Note, traces marked as invalid, e.g. reverted calls, and
+// traces of skipped transactions are ignored.
+func Build(epochNumber uint64, traces []*types.LocalizedTrace, receipts [][]types.TransactionReceipt) (*Ledger, error) {
+	skipped := make(map[types.Hash]bool)
+	for _, blockReceipts := range receipts {
+		for _, v := range blockReceipts {
+			if v.Space == nil {
+				continue
+			}
+
+			if outcome, err := v.GetOutcomeType(); err == nil && outcome == enums.TRANSACTION_OUTCOME_SKIPPED {
+				skipped[v.TransactionHash] = true
+			}
+		}
+	}
+
+	ledger := Ledger{EpochNumber: epochNumber}
+
+	var (
+		lastTx  *types.Hash
+		pending []*types.LocalizedTrace // pending call or create traces to close with result
+	)
+
+	for i, trace := range traces {
+		if trace == nil {
+			continue
+		}
+
+		if trace.TransactionHash != nil && skipped[*trace.TransactionHash] {
+			continue
+		}
+
+		// traces of new transaction
+		if !sameHash(lastTx, trace.TransactionHash) {
+			pending = pending[:0]
+			lastTx = trace.TransactionHash
+		}
+
+		switch action := trace.Action.(type) {
+		case types.Call:
+			reason := ReasonInternalTransfer
+			if len(pending) == 0 {
+				reason = ReasonTransfer
+			}
+
+			pending = append(pending, trace)
+
+			if trace.Valid && action.Value.ToInt().Sign() > 0 {
+				ledger.add(trace, i, reason,
+					Account{action.From.String(), action.Space, types.POCKET_BALANCE},
+					Account{action.To.String(), action.Space, types.POCKET_BALANCE},
+					action.Value.ToInt())
+			}
+		case types.Create:
+			pending = append(pending, trace)
+		case types.CallResult:
+			if len(pending) == 0 {
+				return nil, errors.Errorf("call result without call at trace %v", i)
+			}
+
+			pending = pending[:len(pending)-1]
+		case types.CreateResult:
+			if len(pending) == 0 {
+				return nil, errors.Errorf("create result without create at trace %v", i)
+			}
+
+			create := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			createAction, ok := create.Action.(types.Create)
+			if !ok {
+				return nil, errors.Errorf("create result without create at trace %v", i)
+			}
+
+			reason := ReasonInternalTransfer
+			if len(pending) == 0 {
+				reason = ReasonTransfer
+			}
+
+			// created address is only available in the result
+			if create.Valid && createAction.Value.ToInt().Sign() > 0 {
+				ledger.add(create, i, reason,
+					Account{createAction.From.String(), createAction.Space, types.POCKET_BALANCE},
+					Account{action.Addr.String(), createAction.Space, types.POCKET_BALANCE},
+					createAction.Value.ToInt())
+			}
+		case types.InternalTransferAction:
+			if trace.Valid && action.Value.ToInt().Sign() > 0 {
+				ledger.add(trace, i, internalTransferReason(action),
+					Account{action.From.String(), action.FromSpace, action.FromPocket},
+					Account{action.To.String(), action.ToSpace, action.ToPocket},
+					action.Value.ToInt())
+			}
+		default:
+			return nil, errors.Errorf("unknown action type %T at trace %v", trace.Action, i)
+		}
+	}
+
+	return &ledger, nil
+}
+
+func (l *Ledger) add(trace *types.LocalizedTrace, index int, reason Reason, from, to Account, value *big.Int) {
+	l.Entries = append(l.Entries,
+		Entry{trace.TransactionHash, index, from, new(big.Int).Neg(value), reason},
+		Entry{trace.TransactionHash, index, to, new(big.Int).Set(value), reason},
+	)
+}
+
+func internalTransferReason(action types.InternalTransferAction) Reason {
+	switch {
+	case action.FromPocket == types.POCKET_MINT_BURN:
+		return ReasonMint
+	case action.ToPocket == types.POCKET_MINT_BURN:
+		return ReasonBurn
+	case action.ToPocket == types.POCKET_GAS_PAYMENT && action.FromPocket == types.POCKET_SPONSOR_BALANCE_FOR_GAS:
+		return ReasonGasSponsored
+	case action.ToPocket == types.POCKET_GAS_PAYMENT:
+		return ReasonGasPayment
+	case action.FromPocket == types.POCKET_GAS_PAYMENT:
+		return ReasonGasRefund
+	case action.ToPocket == types.POCKET_STORAGE_COLLATERAL:
+		return ReasonStorageCollateralLocked
+	case action.FromPocket == types.POCKET_STORAGE_COLLATERAL:
+		return ReasonStorageCollateralReleased
+	case action.ToPocket == types.POCKET_STAKING_BALANCE:
+		return ReasonStakingDeposit
+	case action.FromPocket == types.POCKET_STAKING_BALANCE:
+		return ReasonStakingWithdraw
+	case isSponsorPocket(action.ToPocket):
+		return ReasonSponsorDeposit
+	case isSponsorPocket(action.FromPocket):
+		return ReasonSponsorRefund
+	default:
+		return ReasonOther
+	}
+}
+
+func isSponsorPocket(pocket types.PocketType) bool {
+	return pocket == types.POCKET_SPONSOR_BALANCE_FOR_GAS || pocket == types.POCKET_SPONSOR_BALANCE_FOR_STORAGE
+}
+
+func sameHash(a, b *types.Hash) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// Verify verifies the entries are balanced, namely the amounts sum to zero for each value flow.
+func (l *Ledger) Verify() error {
+	if len(l.Entries)%2 != 0 {
+		return errors.Errorf("odd number of entries %v", len(l.Entries))
+	}
+
+	for i := 0; i < len(l.Entries); i += 2 {
+		debit, credit := l.Entries[i], l.Entries[i+1]
+
+		if debit.Reason != credit.Reason || debit.TraceIndex != credit.TraceIndex {
+			return errors.Errorf("entries %v and %v are not paired", i, i+1)
+		}
+
+		if new(big.Int).Add(debit.Amount, credit.Amount).Sign() != 0 {
+			return errors.Errorf("entries %v and %v are not balanced", i, i+1)
+		}
+	}
+
+	return nil
+}
+
+// Changes returns the net amount changes of all accounts.
+func (l *Ledger) Changes() map[Account]*big.Int {
+	changes := make(map[Account]*big.Int)
+
+	for _, v := range l.Entries {
+		if _, ok := changes[v.Account]; !ok {
+			changes[v.Account] = new(big.Int)
+		}
+
+		changes[v.Account].Add(changes[v.Account], v.Amount)
+	}
+
+	return changes
+}
+
+// BalanceChange returns the net change of balance pocket of the specified core space address.
+func (l *Ledger) BalanceChange(address types.Address) *big.Int {
+	change := new(big.Int)
+
+	account := Account{address.String(), types.SPACE_NATIVE, types.POCKET_BALANCE}
+	for _, v := range l.Entries {
+		if v.Account == account {
+			change.Add(change, v.Amount)
+		}
+	}
+
+	return change
+}
+
+// Addresses returns the sorted core space addresses of which the balance changed.
+func (l *Ledger) Addresses() []string {
+	addresses := make(map[string]bool)
+
+	for _, v := range l.Entries {
+		if v.Account.Space == types.SPACE_NATIVE && v.Account.Pocket == types.POCKET_BALANCE {
+			addresses[v.Account.Address] = true
+		}
+	}
+
+	var result []string
+	for v := range addresses {
+		result = append(result, v)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
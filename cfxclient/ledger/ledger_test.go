@@ -0,0 +1,122 @@
+package ledger
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	alice    = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000001", 1029)
+	bob      = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000002", 1029)
+	contract = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000003", 1029)
+	created  = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000004", 1029)
+
+	tx1 = types.Hash("0x0000000000000000000000000000000000000000000000000000000000000001")
+	tx2 = types.Hash("0x0000000000000000000000000000000000000000000000000000000000000002")
+	tx3 = types.Hash("0x0000000000000000000000000000000000000000000000000000000000000003")
+)
+
+func value(v int64) hexutil.Big {
+	return hexutil.Big(*big.NewInt(v))
+}
+
+func newTrace(tx types.Hash, valid bool, action interface{}) *types.LocalizedTrace {
+	return &types.LocalizedTrace{Action: action, Valid: valid, TransactionHash: &tx}
+}
+
+func newInternalTransfer(tx types.Hash, from types.Address, fromPocket types.PocketType, to types.Address, toPocket types.PocketType, v int64) *types.LocalizedTrace {
+	return newTrace(tx, true, types.InternalTransferAction{
+		From: from, FromPocket: fromPocket, FromSpace: types.SPACE_NATIVE,
+		To: to, ToPocket: toPocket, ToSpace: types.SPACE_NATIVE,
+		Value: value(v),
+	})
+}
+
+func newTestTraces() []*types.LocalizedTrace {
+	return []*types.LocalizedTrace{
+		// tx1: alice calls contract with 100, contract transfers 30 to bob, and 5 to alice in a reverted call
+		newInternalTransfer(tx1, alice, types.POCKET_BALANCE, alice, types.POCKET_GAS_PAYMENT, 10),
+		newTrace(tx1, true, types.Call{Space: types.SPACE_NATIVE, From: alice, To: contract, Value: value(100)}),
+		newTrace(tx1, true, types.Call{Space: types.SPACE_NATIVE, From: contract, To: bob, Value: value(30)}),
+		newTrace(tx1, true, types.CallResult{}),
+		newTrace(tx1, false, types.Call{Space: types.SPACE_NATIVE, From: contract, To: alice, Value: value(5)}),
+		newTrace(tx1, false, types.CallResult{}),
+		newTrace(tx1, true, types.CallResult{}),
+		newInternalTransfer(tx1, alice, types.POCKET_BALANCE, alice, types.POCKET_STORAGE_COLLATERAL, 8),
+		newInternalTransfer(tx1, alice, types.POCKET_GAS_PAYMENT, alice, types.POCKET_BALANCE, 2),
+		// tx2: bob creates contract with 7, and gas sponsored
+		newInternalTransfer(tx2, contract, types.POCKET_SPONSOR_BALANCE_FOR_GAS, bob, types.POCKET_GAS_PAYMENT, 3),
+		newTrace(tx2, true, types.Create{Space: types.SPACE_NATIVE, From: bob, Value: value(7)}),
+		newTrace(tx2, true, types.CreateResult{Addr: created}),
+		// tx3: skipped
+		newTrace(tx3, true, types.Call{Space: types.SPACE_NATIVE, From: alice, To: bob, Value: value(1000)}),
+		newTrace(tx3, true, types.CallResult{}),
+	}
+}
+
+func newTestReceipts() [][]types.TransactionReceipt {
+	space := types.SPACE_NATIVE
+
+	return [][]types.TransactionReceipt{{
+		{TransactionHash: tx1, Space: &space, OutcomeStatus: 0},
+		{TransactionHash: tx2, Space: &space, OutcomeStatus: 0},
+		{TransactionHash: tx3, Space: &space, OutcomeStatus: 2},
+	}}
+}
+
+func TestBuild(t *testing.T) {
+	ledger, err := Build(10, newTestTraces(), newTestReceipts())
+	assert.NoError(t, err)
+	assert.NoError(t, ledger.Verify())
+
+	var reasons []Reason
+	for i := 0; i < len(ledger.Entries); i += 2 {
+		reasons = append(reasons, ledger.Entries[i].Reason)
+	}
+
+	assert.Equal(t, []Reason{
+		ReasonGasPayment, ReasonTransfer, ReasonInternalTransfer, ReasonStorageCollateralLocked, ReasonGasRefund,
+		ReasonGasSponsored, ReasonTransfer,
+	}, reasons)
+
+	assert.Equal(t, big.NewInt(-116), ledger.BalanceChange(alice))
+	assert.Equal(t, big.NewInt(30-7), ledger.BalanceChange(bob))
+	assert.Equal(t, big.NewInt(70), ledger.BalanceChange(contract))
+	assert.Equal(t, big.NewInt(7), ledger.BalanceChange(created))
+
+	changes := ledger.Changes()
+	assert.Equal(t, big.NewInt(8), changes[Account{alice.String(), types.SPACE_NATIVE, types.POCKET_STORAGE_COLLATERAL}])
+	assert.Equal(t, big.NewInt(-3), changes[Account{contract.String(), types.SPACE_NATIVE, types.POCKET_SPONSOR_BALANCE_FOR_GAS}])
+
+	total := new(big.Int)
+	for _, v := range changes {
+		total.Add(total, v)
+	}
+	assert.Equal(t, 0, total.Sign())
+
+	assert.Equal(t, []string{alice.String(), bob.String(), contract.String(), created.String()}, ledger.Addresses())
+}
+
+func TestBuildUnpairedResult(t *testing.T) {
+	_, err := Build(10, []*types.LocalizedTrace{newTrace(tx1, true, types.CallResult{})}, nil)
+	assert.Error(t, err)
+}
+
+func TestInternalTransferReason(t *testing.T) {
+	reason := func(from, to types.PocketType) Reason {
+		return internalTransferReason(types.InternalTransferAction{FromPocket: from, ToPocket: to})
+	}
+
+	assert.Equal(t, ReasonStakingDeposit, reason(types.POCKET_BALANCE, types.POCKET_STAKING_BALANCE))
+	assert.Equal(t, ReasonStakingWithdraw, reason(types.POCKET_STAKING_BALANCE, types.POCKET_BALANCE))
+	assert.Equal(t, ReasonStorageCollateralReleased, reason(types.POCKET_STORAGE_COLLATERAL, types.POCKET_BALANCE))
+	assert.Equal(t, ReasonSponsorDeposit, reason(types.POCKET_BALANCE, types.POCKET_SPONSOR_BALANCE_FOR_STORAGE))
+	assert.Equal(t, ReasonSponsorRefund, reason(types.POCKET_SPONSOR_BALANCE_FOR_GAS, types.POCKET_BALANCE))
+	assert.Equal(t, ReasonMint, reason(types.POCKET_MINT_BURN, types.POCKET_BALANCE))
+	assert.Equal(t, ReasonBurn, reason(types.POCKET_GAS_PAYMENT, types.POCKET_MINT_BURN))
+}
@@ -0,0 +1,94 @@
+package ledger
+
+import (
+	"math/big"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/pkg/errors"
+)
+
+// Mismatch is the balance change of address that does not reconcile with the ledger.
+type Mismatch struct {
+	Address        types.Address
+	LedgerChange   *big.Int // net change of balance pocket in ledger
+	BalanceChange  *big.Int // balance delta returned by GetBalance
+	UntracedChange *big.Int // BalanceChange - LedgerChange
+}
+
+// FromEpoch builds the ledger of the specified epoch with core space traces and receipts queried from client.
+func FromEpoch(client sdk.ClientOperator, epochNumber uint64) (*Ledger, error) {
+	epoch := types.NewEpochNumberUint64(epochNumber)
+
+	traces, err := client.Trace().GetEpochTraces(*epoch)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get traces of epoch %v", epochNumber)
+	}
+
+	receipts, err := client.GetEpochReceipts(*types.NewEpochOrBlockHashWithEpoch(epoch))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get receipts of epoch %v", epochNumber)
+	}
+
+	ledger, err := Build(epochNumber, traces.CfxTraces, receipts)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to build ledger of epoch %v", epochNumber)
+	}
+
+	return ledger, nil
+}
+
+// Reconcile compares the balance change of core space addresses in ledger with the balance delta between
+// the previous epoch and the ledger epoch, and returns the mismatched addresses. If no address specified,
+// all addresses of which the balance changed in ledger will be reconciled.
+//
+// Note, some value flows are not traced, e.g. the block rewards of miners and the staking interest, so
+// mismatches are expected for such addresses.
+func Reconcile(client sdk.ClientOperator, ledger *Ledger, addresses ...types.Address) ([]Mismatch, error) {
+	if ledger.EpochNumber == 0 {
+		return nil, errors.New("genesis epoch is not supported")
+	}
+
+	if len(addresses) == 0 {
+		for _, v := range ledger.Addresses() {
+			address, err := cfxaddress.NewFromBase32(v)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to parse address %v", v)
+			}
+
+			addresses = append(addresses, address)
+		}
+	}
+
+	before := types.NewEpochOrBlockHashWithEpoch(types.NewEpochNumberUint64(ledger.EpochNumber - 1))
+	after := types.NewEpochOrBlockHashWithEpoch(types.NewEpochNumberUint64(ledger.EpochNumber))
+
+	var mismatches []Mismatch
+
+	for _, address := range addresses {
+		balanceBefore, err := client.GetBalance(address, before)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get balance of %v at epoch %v", address, ledger.EpochNumber-1)
+		}
+
+		balanceAfter, err := client.GetBalance(address, after)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get balance of %v at epoch %v", address, ledger.EpochNumber)
+		}
+
+		balanceChange := new(big.Int).Sub(balanceAfter.ToInt(), balanceBefore.ToInt())
+		ledgerChange := ledger.BalanceChange(address)
+
+		if balanceChange.Cmp(ledgerChange) != 0 {
+			mismatches = append(mismatches, Mismatch{
+				Address:        address,
+				LedgerChange:   ledgerChange,
+				BalanceChange:  balanceChange,
+				UntracedChange: new(big.Int).Sub(balanceChange, ledgerChange),
+			})
+		}
+	}
+
+	return mismatches, nil
+}
@@ -0,0 +1,96 @@
+package posreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	summaryHeader = []string{
+		"address", "totalReward", "rewardedEpochs", "committeeEpochs", "missedElections",
+		"forcedRetirements", "lastStatus", "powAddresses",
+	}
+
+	epochsHeader = []string{
+		"address", "epoch", "blockNumber", "status", "votingPower", "availableVotes", "reward",
+	}
+)
+
+// WriteJSON writes the report in JSON format, in which rewards are in Drip.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(r); err != nil {
+		return errors.Wrap(err, "failed to encode report in JSON")
+	}
+
+	return nil
+}
+
+// WriteCSV writes the summary of accounts in CSV format, one account per line, in which rewards are in Drip,
+// and the PoW addresses that received rewards are separated by ";".
+func (r *Report) WriteCSV(w io.Writer) error {
+	records := [][]string{summaryHeader}
+
+	for _, v := range r.Accounts {
+		var lastStatus string
+		if len(v.Epochs) > 0 {
+			lastStatus = string(v.Epochs[len(v.Epochs)-1].Status)
+		}
+
+		var powAddresses []string
+		for address := range v.RewardsByPowAddress {
+			powAddresses = append(powAddresses, address)
+		}
+		sort.Strings(powAddresses)
+
+		records = append(records, []string{
+			v.Address.Hex(),
+			v.TotalReward.String(),
+			strconv.Itoa(v.RewardedEpochs),
+			strconv.Itoa(v.CommitteeEpochs),
+			strconv.Itoa(v.MissedElections),
+			strconv.Itoa(v.ForcedRetirements),
+			lastStatus,
+			strings.Join(powAddresses, ";"),
+		})
+	}
+
+	return writeCSV(w, records)
+}
+
+// WriteEpochsCSV writes the per epoch records of accounts in CSV format, in which rewards are in Drip.
+func (r *Report) WriteEpochsCSV(w io.Writer) error {
+	records := [][]string{epochsHeader}
+
+	for _, account := range r.Accounts {
+		for _, v := range account.Epochs {
+			records = append(records, []string{
+				account.Address.Hex(),
+				strconv.FormatUint(v.Epoch, 10),
+				strconv.FormatUint(v.BlockNumber, 10),
+				string(v.Status),
+				strconv.FormatUint(v.VotingPower, 10),
+				strconv.FormatUint(v.AvailableVotes, 10),
+				v.Reward.String(),
+			})
+		}
+	}
+
+	return writeCSV(w, records)
+}
+
+func writeCSV(w io.Writer, records [][]string) error {
+	if err := csv.NewWriter(w).WriteAll(records); err != nil {
+		return errors.Wrap(err, "failed to write CSV")
+	}
+
+	return nil
+}
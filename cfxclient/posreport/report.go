@@ -0,0 +1,243 @@
+// Package posreport aggregates the rewards, committee membership and status transitions of PoS accounts
+// over a range of PoS epochs, and exports the report as CSV or JSON.
+package posreport
+
+import (
+	"math/big"
+	"sort"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+)
+
+// AccountStatus is the status of PoS account at the end of an epoch
+type AccountStatus string
+
+const (
+	// StatusInactive means the account has no available votes
+	StatusInactive AccountStatus = "inactive"
+	// StatusCandidate means the account has available votes but not in committee
+	StatusCandidate AccountStatus = "candidate"
+	// StatusCommittee means the account is a member of committee
+	StatusCommittee AccountStatus = "committee"
+	// StatusForceRetired means the account is force retired, e.g. due to misbehavior or offline
+	StatusForceRetired AccountStatus = "forceRetired"
+)
+
+// EpochSnapshot is the state of PoS chain at the end of an epoch
+type EpochSnapshot struct {
+	Epoch       uint64
+	BlockNumber uint64                                 // last PoS block number of epoch
+	Committee   map[postypes.Address]uint64            // committee member => voting power
+	Rewards     []postypes.Reward                      // rewards distributed in epoch
+	Accounts    map[postypes.Address]*postypes.Account // account status at BlockNumber
+}
+
+// EpochRecord is the state of PoS account in an epoch
+type EpochRecord struct {
+	Epoch          uint64        `json:"epoch"`
+	BlockNumber    uint64        `json:"blockNumber"`
+	Status         AccountStatus `json:"status"`
+	VotingPower    uint64        `json:"votingPower"`
+	AvailableVotes uint64        `json:"availableVotes"`
+	Reward         *big.Int      `json:"reward"`
+}
+
+// StatusTransition represents the status change of PoS account
+type StatusTransition struct {
+	Epoch uint64        `json:"epoch"`
+	From  AccountStatus `json:"from"`
+	To    AccountStatus `json:"to"`
+}
+
+// AccountReport is the aggregated report of PoS account
+type AccountReport struct {
+	Address             postypes.Address    `json:"address"`
+	TotalReward         *big.Int            `json:"totalReward"`
+	RewardsByPowAddress map[string]*big.Int `json:"rewardsByPowAddress"` // PoW address => reward
+	RewardedEpochs      int                 `json:"rewardedEpochs"`
+	CommitteeEpochs     int                 `json:"committeeEpochs"`
+	MissedElections     int                 `json:"missedElections"` // epochs with available votes but not in committee
+	ForcedRetirements   int                 `json:"forcedRetirements"`
+	Transitions         []StatusTransition  `json:"transitions"`
+	Epochs              []EpochRecord       `json:"epochs"`
+}
+
+// Report is the aggregated report of PoS accounts in epoch range [FromEpoch, ToEpoch]
+type Report struct {
+	FromEpoch uint64           `json:"fromEpoch"`
+	ToEpoch   uint64           `json:"toEpoch"`
+	Accounts  []*AccountReport `json:"accounts"` // sorted by address
+
+	initialized bool
+	accounts    map[postypes.Address]*AccountReport
+	filter      map[postypes.Address]bool
+}
+
+// NewReport creates an empty report to aggregate epoch snapshots. If accounts specified, only
+// these accounts are reported, otherwise, all rewarded or committee accounts are reported.
+func NewReport(accounts ...postypes.Address) *Report {
+	report := Report{
+		accounts: make(map[postypes.Address]*AccountReport),
+	}
+
+	if len(accounts) > 0 {
+		report.filter = make(map[postypes.Address]bool)
+		for _, v := range accounts {
+			report.filter[v] = true
+			report.account(v)
+		}
+
+		report.sortAccounts()
+	}
+
+	return &report
+}
+
+// Add aggregates the epoch snapshot into report, which should be added in epoch order.
+func (r *Report) Add(snapshot *EpochSnapshot) {
+	if !r.initialized {
+		r.FromEpoch = snapshot.Epoch
+		r.initialized = true
+	}
+	r.ToEpoch = snapshot.Epoch
+
+	rewards := make(map[postypes.Address]*big.Int)
+	for _, v := range snapshot.Rewards {
+		account := r.account(v.PosAddress)
+		if account == nil {
+			continue
+		}
+
+		reward := v.Reward.ToInt()
+		account.TotalReward.Add(account.TotalReward, reward)
+
+		powAddress := v.PowAddress.String()
+		if _, ok := account.RewardsByPowAddress[powAddress]; !ok {
+			account.RewardsByPowAddress[powAddress] = new(big.Int)
+		}
+		account.RewardsByPowAddress[powAddress].Add(account.RewardsByPowAddress[powAddress], reward)
+
+		if _, ok := rewards[v.PosAddress]; !ok {
+			rewards[v.PosAddress] = new(big.Int)
+			account.RewardedEpochs++
+		}
+		rewards[v.PosAddress].Add(rewards[v.PosAddress], reward)
+	}
+
+	for address := range snapshot.Committee {
+		r.account(address)
+	}
+
+	for address, account := range r.accounts {
+		record := EpochRecord{
+			Epoch:       snapshot.Epoch,
+			BlockNumber: snapshot.BlockNumber,
+			Reward:      rewards[address],
+		}
+
+		if record.Reward == nil {
+			record.Reward = new(big.Int)
+		}
+
+		if status, ok := snapshot.Accounts[address]; ok && status != nil {
+			record.AvailableVotes = uint64(status.Status.AvailableVotes)
+		}
+
+		record.VotingPower, record.Status = r.status(snapshot, address)
+
+		switch record.Status {
+		case StatusCommittee:
+			account.CommitteeEpochs++
+		case StatusCandidate:
+			account.MissedElections++
+		}
+
+		if len(account.Epochs) > 0 {
+			if last := account.Epochs[len(account.Epochs)-1].Status; last != record.Status {
+				account.Transitions = append(account.Transitions, StatusTransition{snapshot.Epoch, last, record.Status})
+
+				if record.Status == StatusForceRetired {
+					account.ForcedRetirements++
+				}
+			}
+		}
+
+		account.Epochs = append(account.Epochs, record)
+	}
+
+	r.sortAccounts()
+}
+
+func (r *Report) status(snapshot *EpochSnapshot, address postypes.Address) (uint64, AccountStatus) {
+	account := snapshot.Accounts[address]
+	if account != nil && account.Status.ForceRetired != nil {
+		return 0, StatusForceRetired
+	}
+
+	if votingPower, ok := snapshot.Committee[address]; ok {
+		return votingPower, StatusCommittee
+	}
+
+	if account != nil && account.Status.AvailableVotes > 0 {
+		return 0, StatusCandidate
+	}
+
+	return 0, StatusInactive
+}
+
+// account returns the account report to aggregate, or nil if account is not reported.
+func (r *Report) account(address postypes.Address) *AccountReport {
+	if r.filter != nil && !r.filter[address] {
+		return nil
+	}
+
+	if account, ok := r.accounts[address]; ok {
+		return account
+	}
+
+	account := AccountReport{
+		Address:             address,
+		TotalReward:         new(big.Int),
+		RewardsByPowAddress: make(map[string]*big.Int),
+	}
+	r.accounts[address] = &account
+
+	return &account
+}
+
+func (r *Report) sortAccounts() {
+	r.Accounts = r.Accounts[:0]
+	for _, v := range r.accounts {
+		r.Accounts = append(r.Accounts, v)
+	}
+
+	sort.Slice(r.Accounts, func(i, j int) bool {
+		return r.Accounts[i].Address.Hex() < r.Accounts[j].Address.Hex()
+	})
+}
+
+// trackedAccounts returns the accounts to query status in the epoch snapshot.
+func (r *Report) trackedAccounts(snapshot *EpochSnapshot) []postypes.Address {
+	accounts := make(map[postypes.Address]bool)
+	for v := range r.accounts {
+		accounts[v] = true
+	}
+
+	// only specified accounts are tracked if filter enabled
+	if r.filter == nil {
+		for v := range snapshot.Committee {
+			accounts[v] = true
+		}
+
+		for _, v := range snapshot.Rewards {
+			accounts[v.PosAddress] = true
+		}
+	}
+
+	var result []postypes.Address
+	for v := range accounts {
+		result = append(result, v)
+	}
+
+	return result
+}
@@ -0,0 +1,156 @@
+package posreport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	accountA = common.HexToHash("0x0a")
+	accountB = common.HexToHash("0x0b")
+
+	powA  = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000001", 1029)
+	powB1 = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000002", 1029)
+	powB2 = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000003", 1029)
+)
+
+func newReward(account postypes.Address, pow cfxaddress.Address, reward int64) postypes.Reward {
+	return postypes.Reward{
+		PosAddress: account,
+		PowAddress: pow,
+		Reward:     hexutil.Big(*big.NewInt(reward)),
+	}
+}
+
+func newAccount(address postypes.Address, availableVotes uint64, forceRetired bool) *postypes.Account {
+	account := postypes.Account{
+		Address: address,
+		Status:  postypes.NodeLockStatus{AvailableVotes: hexutil.Uint64(availableVotes)},
+	}
+
+	if forceRetired {
+		retired := hexutil.Uint64(1)
+		account.Status.ForceRetired = &retired
+	}
+
+	return &account
+}
+
+func newTestSnapshots() []*EpochSnapshot {
+	return []*EpochSnapshot{
+		{
+			Epoch:       10,
+			BlockNumber: 100,
+			Committee:   map[postypes.Address]uint64{accountA: 5, accountB: 3},
+			Rewards:     []postypes.Reward{newReward(accountA, powA, 10), newReward(accountB, powB1, 6)},
+			Accounts: map[postypes.Address]*postypes.Account{
+				accountA: newAccount(accountA, 5, false),
+				accountB: newAccount(accountB, 3, false),
+			},
+		},
+		{
+			Epoch:       11,
+			BlockNumber: 200,
+			Committee:   map[postypes.Address]uint64{accountB: 3},
+			Rewards:     []postypes.Reward{newReward(accountB, powB2, 4), newReward(accountB, powB2, 1)},
+			Accounts: map[postypes.Address]*postypes.Account{
+				accountA: newAccount(accountA, 5, false),
+				accountB: newAccount(accountB, 3, false),
+			},
+		},
+		{
+			Epoch:       12,
+			BlockNumber: 300,
+			Committee:   map[postypes.Address]uint64{accountB: 3},
+			Accounts: map[postypes.Address]*postypes.Account{
+				accountA: newAccount(accountA, 5, true),
+				accountB: newAccount(accountB, 3, false),
+			},
+		},
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := NewReport()
+	for _, v := range newTestSnapshots() {
+		report.Add(v)
+	}
+
+	assert.Equal(t, uint64(10), report.FromEpoch)
+	assert.Equal(t, uint64(12), report.ToEpoch)
+	assert.Equal(t, 2, len(report.Accounts))
+
+	a := report.Accounts[0]
+	assert.Equal(t, accountA, a.Address)
+	assert.Equal(t, big.NewInt(10), a.TotalReward)
+	assert.Equal(t, 1, a.RewardedEpochs)
+	assert.Equal(t, 1, a.CommitteeEpochs)
+	assert.Equal(t, 1, a.MissedElections)
+	assert.Equal(t, 1, a.ForcedRetirements)
+	assert.Equal(t, []StatusTransition{
+		{11, StatusCommittee, StatusCandidate},
+		{12, StatusCandidate, StatusForceRetired},
+	}, a.Transitions)
+
+	b := report.Accounts[1]
+	assert.Equal(t, accountB, b.Address)
+	assert.Equal(t, big.NewInt(11), b.TotalReward)
+	assert.Equal(t, 2, b.RewardedEpochs)
+	assert.Equal(t, 3, b.CommitteeEpochs)
+	assert.Equal(t, map[string]*big.Int{powB1.String(): big.NewInt(6), powB2.String(): big.NewInt(5)}, b.RewardsByPowAddress)
+	assert.Empty(t, b.Transitions)
+	assert.Equal(t, EpochRecord{11, 200, StatusCommittee, 3, 3, big.NewInt(5)}, b.Epochs[1])
+}
+
+func TestReportFilter(t *testing.T) {
+	report := NewReport(accountB)
+	for _, v := range newTestSnapshots() {
+		report.Add(v)
+	}
+
+	assert.Equal(t, 1, len(report.Accounts))
+	assert.Equal(t, accountB, report.Accounts[0].Address)
+	assert.ElementsMatch(t, []postypes.Address{accountB}, report.trackedAccounts(newTestSnapshots()[0]))
+}
+
+func TestReportExport(t *testing.T) {
+	report := NewReport()
+	for _, v := range newTestSnapshots() {
+		report.Add(v)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, summaryHeader, records[0])
+	assert.Equal(t, []string{
+		accountB.Hex(), "11", "2", "3", "0", "0", string(StatusCommittee), powB1.String() + ";" + powB2.String(),
+	}, records[2])
+
+	buf.Reset()
+	assert.NoError(t, report.WriteEpochsCSV(&buf))
+
+	records, err = csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, len(records))
+	assert.Equal(t, []string{accountA.Hex(), "12", "300", string(StatusForceRetired), "0", "5", "0"}, records[3])
+
+	buf.Reset()
+	assert.NoError(t, report.WriteJSON(&buf))
+
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report.Accounts, decoded.Accounts)
+}
@@ -0,0 +1,173 @@
+package posreport
+
+import (
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/cfxclient/bulk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// DefaultBatchEpochs is the default number of epochs to query in a bulk request
+const DefaultBatchEpochs = 20
+
+// Reporter scans PoS epochs in bulk to generate report
+type Reporter struct {
+	client      sdk.ClientOperator
+	batchEpochs uint64
+}
+
+// NewReporter creates a reporter, and DefaultBatchEpochs is used if batchEpochs is 0.
+func NewReporter(client sdk.ClientOperator, batchEpochs uint64) *Reporter {
+	if batchEpochs == 0 {
+		batchEpochs = DefaultBatchEpochs
+	}
+
+	return &Reporter{client, batchEpochs}
+}
+
+// Report scans the ended PoS epochs in range [fromEpoch, toEpoch] to generate report. If accounts specified,
+// only these accounts are reported, otherwise, all rewarded or committee accounts are reported.
+func (r *Reporter) Report(fromEpoch, toEpoch uint64, accounts ...postypes.Address) (*Report, error) {
+	if fromEpoch > toEpoch {
+		return nil, errors.Errorf("invalid epoch range [%v, %v]", fromEpoch, toEpoch)
+	}
+
+	report := NewReport(accounts...)
+
+	for start := fromEpoch; start <= toEpoch; start += r.batchEpochs {
+		end := start + r.batchEpochs - 1
+		if end > toEpoch {
+			end = toEpoch
+		}
+
+		snapshots, err := r.snapshots(start, end)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get snapshots of epochs [%v, %v]", start, end)
+		}
+
+		for _, v := range snapshots {
+			if err = r.fillAccounts(v, report.trackedAccounts(v)); err != nil {
+				return nil, errors.WithMessagef(err, "failed to get accounts of epoch %v", v.Epoch)
+			}
+
+			report.Add(v)
+		}
+	}
+
+	return report, nil
+}
+
+// snapshots returns the epoch snapshots without account status in epoch range [from, to].
+func (r *Reporter) snapshots(from, to uint64) ([]*EpochSnapshot, error) {
+	type epochResult struct {
+		rewards       *postypes.EpochReward
+		rewardsErr    *error
+		state         *postypes.EpochState
+		stateErr      *error
+		ledgerInfo    *postypes.LedgerInfoWithSignatures
+		ledgerInfoErr *error
+	}
+
+	caller := bulk.NewBulkCaller(r.client)
+
+	var results []epochResult
+	for epoch := from; epoch <= to; epoch++ {
+		var result epochResult
+		result.rewards, result.rewardsErr = caller.Pos().GetRewardsByEpoch(epoch)
+		result.state, result.stateErr = caller.Pos().GetEpochState(epoch)
+		result.ledgerInfo, result.ledgerInfoErr = caller.Pos().GetLedgerInfoByEpoch(epoch)
+		results = append(results, result)
+	}
+
+	if err := caller.Execute(); err != nil {
+		return nil, errors.WithMessage(err, "failed to execute bulk request")
+	}
+
+	// query the last block of epochs to get the block numbers
+	caller.Clear()
+
+	var blocks []*postypes.Block
+	var blockErrs []*error
+	for i, v := range results {
+		epoch := from + uint64(i)
+
+		for _, err := range []*error{v.rewardsErr, v.stateErr, v.ledgerInfoErr} {
+			if *err != nil {
+				return nil, errors.WithMessagef(*err, "failed to query epoch %v", epoch)
+			}
+		}
+
+		if len(v.ledgerInfo.LedgerInfo.CommitInfo.Id) == 0 {
+			return nil, errors.Errorf("epoch %v is not ended yet", epoch)
+		}
+
+		blockHash := types.Hash(hexutil.Encode(v.ledgerInfo.LedgerInfo.CommitInfo.Id))
+		block, err := caller.Pos().GetBlockByHash(blockHash)
+		blocks = append(blocks, block)
+		blockErrs = append(blockErrs, err)
+	}
+
+	if err := caller.Execute(); err != nil {
+		return nil, errors.WithMessage(err, "failed to execute bulk request")
+	}
+
+	var snapshots []*EpochSnapshot
+	for i, v := range results {
+		epoch := from + uint64(i)
+
+		if *blockErrs[i] != nil {
+			return nil, errors.WithMessagef(*blockErrs[i], "failed to get last block of epoch %v", epoch)
+		}
+
+		if blocks[i].Hash == (common.Hash{}) {
+			return nil, errors.Errorf("last block of epoch %v not found", epoch)
+		}
+
+		committee := make(map[postypes.Address]uint64)
+		for address, info := range v.state.Verifier.AddressToValidatorInfo {
+			committee[address] = uint64(info.VotingPower)
+		}
+
+		snapshots = append(snapshots, &EpochSnapshot{
+			Epoch:       epoch,
+			BlockNumber: uint64(blocks[i].Height),
+			Committee:   committee,
+			Rewards:     v.rewards.AccountRewards,
+		})
+	}
+
+	return snapshots, nil
+}
+
+// fillAccounts queries the status of specified accounts at the last block of epoch.
+func (r *Reporter) fillAccounts(snapshot *EpochSnapshot, accounts []postypes.Address) error {
+	snapshot.Accounts = make(map[postypes.Address]*postypes.Account)
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	caller := bulk.NewBulkCaller(r.client)
+
+	results := make([]*postypes.Account, len(accounts))
+	errs := make([]*error, len(accounts))
+	for i, v := range accounts {
+		results[i], errs[i] = caller.Pos().GetAccount(v, snapshot.BlockNumber)
+	}
+
+	if err := caller.Execute(); err != nil {
+		return errors.WithMessage(err, "failed to execute bulk request")
+	}
+
+	for i, v := range accounts {
+		if *errs[i] != nil {
+			return errors.WithMessagef(*errs[i], "failed to get account %v", v)
+		}
+
+		snapshot.Accounts[v] = results[i]
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package posvalidator
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// BlsPublicKeyProofPartLength is the length of each part of the BLS proof of possession,
+	// which is the uncompressed G2 signature split into two halves.
+	BlsPublicKeyProofPartLength = 96
+)
+
+// KeyPair contains the BLS and VRF keys of a PoS validator
+type KeyPair struct {
+	BlsPrivateKey *big.Int
+	VrfPrivateKey *ecdsa.PrivateKey
+}
+
+// GenerateKeyPair generates a random BLS12-381 private key and a random secp256k1 VRF private key
+func GenerateKeyPair() (*KeyPair, error) {
+	blsKey, err := rand.Int(rand.Reader, bls12381.NewG1().Q())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate BLS private key")
+	}
+
+	vrfKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate VRF private key")
+	}
+
+	return &KeyPair{blsKey, vrfKey}, nil
+}
+
+// NewKeyPair creates KeyPair by the raw BLS private key and VRF private key
+func NewKeyPair(blsPrivateKey []byte, vrfPrivateKey []byte) (*KeyPair, error) {
+	blsKey := new(big.Int).SetBytes(blsPrivateKey)
+	if blsKey.Sign() == 0 || blsKey.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, errors.New("invalid BLS private key")
+	}
+
+	vrfKey, err := crypto.ToECDSA(vrfPrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid VRF private key")
+	}
+
+	return &KeyPair{blsKey, vrfKey}, nil
+}
+
+// BlsPublicKey returns the compressed BLS public key in 48 bytes, which is used for registration
+func (k *KeyPair) BlsPublicKey() []byte {
+	g1 := bls12381.NewG1()
+	return g1.ToCompressed(k.blsPublicKeyPoint())
+}
+
+// BlsPublicKeyUncompressed returns the uncompressed BLS public key in 96 bytes
+func (k *KeyPair) BlsPublicKeyUncompressed() []byte {
+	g1 := bls12381.NewG1()
+	return g1.ToBytes(k.blsPublicKeyPoint())
+}
+
+// VrfPublicKey returns the compressed secp256k1 VRF public key in 33 bytes
+func (k *KeyPair) VrfPublicKey() []byte {
+	return crypto.CompressPubkey(&k.VrfPrivateKey.PublicKey)
+}
+
+// Identifier returns the PoS account address of the key pair
+func (k *KeyPair) Identifier() common.Hash {
+	return ComputeIdentifier(k.BlsPublicKey(), k.VrfPublicKey())
+}
+
+// ProofOfPossession signs the BLS public key with the BLS private key to prove the ownership of the BLS private key
+func (k *KeyPair) ProofOfPossession() ([2][]byte, error) {
+	signature, err := postypes.SignBLS(k.BlsPrivateKey, k.BlsPublicKey())
+	if err != nil {
+		return [2][]byte{}, errors.WithMessage(err, "failed to sign BLS public key")
+	}
+
+	return [2][]byte{
+		signature[:BlsPublicKeyProofPartLength],
+		signature[BlsPublicKeyProofPartLength:],
+	}, nil
+}
+
+func (k *KeyPair) blsPublicKeyPoint() *bls12381.PointG1 {
+	g1 := bls12381.NewG1()
+	return g1.MulScalarBig(g1.New(), g1.One(), k.BlsPrivateKey)
+}
+
+// ComputeIdentifier returns the PoS account address by the compressed BLS public key and VRF public key,
+// which is the SHA3-256 hash of the concatenated public keys.
+func ComputeIdentifier(blsPublicKey []byte, vrfPublicKey []byte) common.Hash {
+	hasher := sha3.New256()
+	hasher.Write(blsPublicKey)
+	hasher.Write(vrfPublicKey)
+	return common.BytesToHash(hasher.Sum(nil))
+}
+
+// VerifyProofOfPossession verifies the BLS proof of possession against the compressed BLS public key
+func VerifyProofOfPossession(blsPublicKey []byte, proof [2][]byte) (bool, error) {
+	g1 := bls12381.NewG1()
+	point, err := g1.FromCompressed(blsPublicKey)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode BLS public key")
+	}
+
+	signature := append(append([]byte{}, proof[0]...), proof[1]...)
+	return postypes.VerifyBLS(signature, g1.ToBytes(point), blsPublicKey)
+}
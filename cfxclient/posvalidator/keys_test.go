@@ -0,0 +1,60 @@
+package posvalidator
+
+import (
+	"testing"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProofOfPossession(t *testing.T) {
+	keyPair, err := GenerateKeyPair()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 48, len(keyPair.BlsPublicKey()))
+	assert.Equal(t, 96, len(keyPair.BlsPublicKeyUncompressed()))
+	assert.Equal(t, 33, len(keyPair.VrfPublicKey()))
+
+	proof, err := keyPair.ProofOfPossession()
+	assert.NoError(t, err)
+
+	ok, err := VerifyProofOfPossession(keyPair.BlsPublicKey(), proof)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	another, err := GenerateKeyPair()
+	assert.NoError(t, err)
+	ok, err = VerifyProofOfPossession(another.BlsPublicKey(), proof)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestNewRegistration(t *testing.T) {
+	keyPair, err := NewKeyPair([]byte{0x01, 0x02, 0x03}, make([]byte, 31))
+	assert.Error(t, err)
+	assert.Nil(t, keyPair)
+
+	keyPair, err = GenerateKeyPair()
+	assert.NoError(t, err)
+
+	_, err = NewRegistration(keyPair, 0)
+	assert.Error(t, err)
+
+	registration, err := NewRegistration(keyPair, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, [32]byte(keyPair.Identifier()), registration.Identifier)
+	assert.Equal(t, uint64(10), registration.VotePower)
+}
+
+func TestBuildTimeline(t *testing.T) {
+	status := postypes.NodeLockStatus{
+		InQueue:  []postypes.VotePowerState{{EndBlockNumber: 300, Power: 1}},
+		OutQueue: []postypes.VotePowerState{{EndBlockNumber: 100, Power: 2}},
+	}
+
+	timeline := BuildTimeline(status)
+	assert.Equal(t, []VoteEvent{
+		{VoteEventUnlock, 100, 2},
+		{VoteEventLock, 300, 1},
+	}, timeline)
+}
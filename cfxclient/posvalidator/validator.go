@@ -0,0 +1,138 @@
+package posvalidator
+
+import (
+	"sort"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/bind"
+	internalcontract "github.com/Conflux-Chain/go-conflux-sdk/contract_meta/internal_contract"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/pkg/errors"
+)
+
+// VoteEventKind is the kind of vote power change in the timeline of PoS account
+type VoteEventKind string
+
+const (
+	// VoteEventLock means the votes in queue will be locked at the block number
+	VoteEventLock VoteEventKind = "lock"
+	// VoteEventUnlock means the votes out queue will be unlocked at the block number
+	VoteEventUnlock VoteEventKind = "unlock"
+)
+
+// Registration contains the arguments of PoSRegister.register
+type Registration struct {
+	Identifier        [32]byte
+	VotePower         uint64
+	BlsPublicKey      []byte
+	VrfPublicKey      []byte
+	BlsPublicKeyProof [2][]byte
+}
+
+// VoteEvent represents a pending vote power change of PoS account
+type VoteEvent struct {
+	Kind        VoteEventKind
+	BlockNumber uint64
+	Power       uint64
+}
+
+// AccountStatus represents the status of PoS account with the lock and unlock timeline
+type AccountStatus struct {
+	postypes.Account
+	Timeline []VoteEvent
+}
+
+// Validator is used to register and manage a PoS validator
+type Validator struct {
+	client   sdk.ClientOperator
+	register internalcontract.PoSRegister
+}
+
+// NewRegistration assembles the arguments of PoSRegister.register with the key pair and vote power
+func NewRegistration(keyPair *KeyPair, votePower uint64) (*Registration, error) {
+	if votePower == 0 {
+		return nil, errors.New("vote power must be positive")
+	}
+
+	proof, err := keyPair.ProofOfPossession()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Registration{
+		Identifier:        keyPair.Identifier(),
+		VotePower:         votePower,
+		BlsPublicKey:      keyPair.BlsPublicKey(),
+		VrfPublicKey:      keyPair.VrfPublicKey(),
+		BlsPublicKeyProof: proof,
+	}, nil
+}
+
+// NewValidator creates a Validator
+func NewValidator(client sdk.ClientOperator) (*Validator, error) {
+	register, err := internalcontract.NewPoSRegister(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get PoSRegister contract")
+	}
+	return &Validator{client, register}, nil
+}
+
+// Register registers the PoS validator of keyPair with votePower, the votes should be locked in staking contract before registering
+func (v *Validator) Register(opts *bind.TransactOpts, keyPair *KeyPair, votePower uint64) (types.Hash, error) {
+	registration, err := NewRegistration(keyPair, votePower)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to create registration")
+	}
+	return v.register.Register(opts, registration.Identifier, registration.VotePower,
+		registration.BlsPublicKey, registration.VrfPublicKey, registration.BlsPublicKeyProof)
+}
+
+// IncreaseStake increases the votes of the registered PoS validator
+func (v *Validator) IncreaseStake(opts *bind.TransactOpts, votePower uint64) (types.Hash, error) {
+	return v.register.IncreaseStake(opts, votePower)
+}
+
+// Retire retires the votes of the registered PoS validator
+func (v *Validator) Retire(opts *bind.TransactOpts, votePower uint64) (types.Hash, error) {
+	return v.register.Retire(opts, votePower)
+}
+
+// GetAccountStatus returns the PoS account status and the lock and unlock timeline of the PoW address
+func (v *Validator) GetAccountStatus(powAddress cfxaddress.Address) (*AccountStatus, error) {
+	account, err := v.client.Pos().GetAccountByPowAddress(powAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get PoS account")
+	}
+
+	return &AccountStatus{
+		Account:  account,
+		Timeline: BuildTimeline(account.Status),
+	}, nil
+}
+
+// IsRegistered returns whether the PoW address has registered a PoS account
+func (v *Validator) IsRegistered(powAddress cfxaddress.Address) (bool, error) {
+	identifier, err := v.register.AddressToIdentifier(nil, powAddress.MustGetCommonAddress())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get PoS identifier")
+	}
+	return identifier != [32]byte{}, nil
+}
+
+// BuildTimeline returns the pending lock and unlock events of the node lock status ordered by block number
+func BuildTimeline(status postypes.NodeLockStatus) []VoteEvent {
+	var timeline []VoteEvent
+	for _, s := range status.InQueue {
+		timeline = append(timeline, VoteEvent{VoteEventLock, uint64(s.EndBlockNumber), uint64(s.Power)})
+	}
+	for _, s := range status.OutQueue {
+		timeline = append(timeline, VoteEvent{VoteEventUnlock, uint64(s.EndBlockNumber), uint64(s.Power)})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].BlockNumber < timeline[j].BlockNumber
+	})
+	return timeline
+}
@@ -0,0 +1,221 @@
+// Package receiptutil interprets the fee, storage collateral and outcome fields of transaction receipts together,
+// e.g. who paid the gas fee, how much of it is burnt and the decoded execution error.
+package receiptutil
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/enums"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils/abiutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// Payer is the payer of gas fee or storage collateral of transaction.
+type Payer string
+
+const (
+	PayerNone    Payer = "none"    // nothing paid, e.g. skipped transaction
+	PayerSender  Payer = "sender"  // paid by the sender of transaction
+	PayerSponsor Payer = "sponsor" // paid by the sponsor of the called contract
+)
+
+// StorageCollateralChange is the net storage collateral change of an address in transaction.
+type StorageCollateralChange struct {
+	Address    types.Address
+	Bytes      int64    // positive for collateralized and negative for released
+	Collateral *big.Int // Bytes * constants.CollateralPerStorageByte in drip
+}
+
+// ExecError is the execution error of failed transaction.
+type ExecError struct {
+	Message string // the original TxExecErrorMsg
+	Reason  string // the revert reason provided by contract if any
+}
+
+func (e *ExecError) Error() string {
+	if e.Reason != "" {
+		return e.Message + ": " + e.Reason
+	}
+
+	return e.Message
+}
+
+// ReceiptSummary is the typed summary of transaction receipt.
+type ReceiptSummary struct {
+	TransactionHash types.Hash
+	Space           types.SpaceType
+	From            types.Address
+	To              *types.Address
+
+	Outcome       enums.TransactionOutcome
+	OutcomeStatus string // e.g. NATIVE_SPACE_SUCCESS or EVM_SPACE_FAIL
+
+	GasUsed           *big.Int
+	GasFee            *big.Int
+	GasPayer          Payer
+	EffectiveGasPrice *big.Int // GasFee / GasUsed if not provided by receipt
+	BurntGasFee       *big.Int // zero if 1559 not activated
+	TipFee            *big.Int // GasFee - BurntGasFee, which is rewarded to miners
+
+	StoragePayer   Payer
+	StorageChanges []StorageCollateralChange // net storage collateral change per address, in order of appearance
+
+	ExecError *ExecError // nil if executed successfully
+}
+
+// Succeeded returns true if transaction executed successfully.
+func (s *ReceiptSummary) Succeeded() bool {
+	return s.Outcome == enums.TRANSACTION_OUTCOME_SUCCESS
+}
+
+// StorageChange returns the net storage collateral change of address in bytes.
+func (s *ReceiptSummary) StorageChange(address types.Address) int64 {
+	for _, v := range s.StorageChanges {
+		if v.Address.String() == address.String() {
+			return v.Bytes
+		}
+	}
+
+	return 0
+}
+
+// AnalyzeReceipt returns the typed summary of receipt of both core space and eSpace, e.g. returned by
+// GetTransactionReceipt or GetEpochReceipts with eSpace receipts included. Receipts without space
+// are regarded as core space receipts.
+func AnalyzeReceipt(receipt *types.TransactionReceipt) (*ReceiptSummary, error) {
+	space := types.SPACE_NATIVE
+	if receipt.Space != nil {
+		space = *receipt.Space
+	}
+
+	copied := *receipt
+	copied.Space = &space
+
+	outcome, err := copied.GetOutcomeType()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get outcome of receipt %v", receipt.TransactionHash)
+	}
+
+	summary := ReceiptSummary{
+		TransactionHash: receipt.TransactionHash,
+		Space:           space,
+		From:            receipt.From,
+		To:              receipt.To,
+		Outcome:         outcome,
+		OutcomeStatus:   outcomeStatus(space, receipt.OutcomeStatus),
+		GasUsed:         bigOf(receipt.GasUsed),
+		GasFee:          bigOf(receipt.GasFee),
+		BurntGasFee:     bigOf(receipt.BurntGasFee),
+		GasPayer:        PayerSender,
+		StoragePayer:    PayerNone,
+	}
+
+	if outcome == enums.TRANSACTION_OUTCOME_SKIPPED {
+		summary.GasPayer = PayerNone
+	} else if receipt.GasCoveredBySponsor {
+		summary.GasPayer = PayerSponsor
+	}
+
+	summary.TipFee = new(big.Int).Sub(summary.GasFee, summary.BurntGasFee)
+
+	switch {
+	case receipt.EffectiveGasPrice != nil:
+		summary.EffectiveGasPrice = receipt.EffectiveGasPrice.ToInt()
+	case summary.GasUsed.Sign() > 0:
+		summary.EffectiveGasPrice = new(big.Int).Div(summary.GasFee, summary.GasUsed)
+	default:
+		summary.EffectiveGasPrice = new(big.Int)
+	}
+
+	summary.collectStorageChanges(receipt)
+
+	if outcome != enums.TRANSACTION_OUTCOME_SUCCESS && receipt.TxExecErrorMsg != nil {
+		summary.ExecError = DecodeExecError(*receipt.TxExecErrorMsg)
+	}
+
+	return &summary, nil
+}
+
+func (s *ReceiptSummary) collectStorageChanges(receipt *types.TransactionReceipt) {
+	if receipt.StorageCollateralized > 0 {
+		// collateral is paid by the called contract if sponsored
+		account, payer := receipt.From, PayerSender
+		if receipt.StorageCoveredBySponsor && receipt.To != nil {
+			account, payer = *receipt.To, PayerSponsor
+		}
+
+		s.StoragePayer = payer
+		s.addStorageChange(account, int64(receipt.StorageCollateralized))
+	}
+
+	for _, v := range receipt.StorageReleased {
+		s.addStorageChange(v.Address, -int64(v.Collaterals))
+	}
+}
+
+func (s *ReceiptSummary) addStorageChange(address types.Address, bytes int64) {
+	for i, v := range s.StorageChanges {
+		if v.Address.String() == address.String() {
+			s.StorageChanges[i].Bytes += bytes
+			s.StorageChanges[i].Collateral = collateralOf(s.StorageChanges[i].Bytes)
+			return
+		}
+	}
+
+	s.StorageChanges = append(s.StorageChanges, StorageCollateralChange{
+		Address:    address,
+		Bytes:      bytes,
+		Collateral: collateralOf(bytes),
+	})
+}
+
+var contractReasonRegexp = regexp.MustCompile(`Reason provided by the contract: '(.*)'`)
+
+// DecodeExecError decodes the TxExecErrorMsg of receipt, e.g. "Vm reverted, Reason provided by the contract: 'denied'"
+// or the revert data in hex of Error(string).
+func DecodeExecError(msg string) *ExecError {
+	execErr := ExecError{Message: msg}
+
+	if matches := contractReasonRegexp.FindStringSubmatch(msg); matches != nil {
+		execErr.Reason = matches[1]
+		return &execErr
+	}
+
+	if i := strings.Index(msg, "0x"); i >= 0 {
+		data, err := hexutil.Decode(strings.TrimSpace(msg[i:]))
+		if err != nil {
+			return &execErr
+		}
+
+		if reason, err := abiutil.DecodeErrData(data); err == nil {
+			execErr.Reason = reason
+		}
+	}
+
+	return &execErr
+}
+
+func outcomeStatus(space types.SpaceType, status hexutil.Uint64) string {
+	if space == types.SPACE_EVM {
+		return enums.EvmSpaceOutcome(status).String()
+	}
+
+	return enums.NativeSpaceOutcome(status).String()
+}
+
+func collateralOf(bytes int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(bytes), constants.CollateralPerStorageByte)
+}
+
+func bigOf(value *hexutil.Big) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(value.ToInt())
+}
@@ -0,0 +1,97 @@
+package receiptutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	user     = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000001", 1029)
+	contract = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000002", 1029)
+)
+
+func TestAnalyzeReceipt(t *testing.T) {
+	space := types.SPACE_NATIVE
+	msg := "Vm reverted, Reason provided by the contract: 'denied'"
+
+	receipt := types.TransactionReceipt{
+		From:                    user,
+		To:                      &contract,
+		GasUsed:                 types.NewBigInt(21000),
+		GasFee:                  types.NewBigInt(42000),
+		BurntGasFee:             types.NewBigInt(21000),
+		OutcomeStatus:           1,
+		TxExecErrorMsg:          &msg,
+		GasCoveredBySponsor:     true,
+		StorageCoveredBySponsor: true,
+		StorageCollateralized:   128,
+		StorageReleased: []types.StorageChange{
+			{Address: user, Collaterals: 64},
+			{Address: contract, Collaterals: 64},
+		},
+		Space: &space,
+	}
+
+	summary, err := AnalyzeReceipt(&receipt)
+	assert.NoError(t, err)
+
+	assert.Equal(t, enums.TRANSACTION_OUTCOME_FAILURE, summary.Outcome)
+	assert.Equal(t, "NATIVE_SPACE_EXCEPTION_WITH_NONCE_BUMPING", summary.OutcomeStatus)
+	assert.False(t, summary.Succeeded())
+	assert.Equal(t, PayerSponsor, summary.GasPayer)
+	assert.Equal(t, big.NewInt(2), summary.EffectiveGasPrice)
+	assert.Equal(t, big.NewInt(21000), summary.TipFee)
+
+	assert.Equal(t, PayerSponsor, summary.StoragePayer)
+	assert.Equal(t, int64(64), summary.StorageChange(contract))
+	assert.Equal(t, int64(-64), summary.StorageChange(user))
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(-64), constants.CollateralPerStorageByte), summary.StorageChanges[1].Collateral)
+
+	assert.Equal(t, "denied", summary.ExecError.Reason)
+	assert.Equal(t, msg+": denied", summary.ExecError.Error())
+}
+
+func TestAnalyzeEvmReceipt(t *testing.T) {
+	space := types.SPACE_EVM
+	msg := "Vm reverted, 0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000006" +
+		"64656e6965640000000000000000000000000000000000000000000000000000"
+
+	receipt := types.TransactionReceipt{
+		From:              user,
+		GasUsed:           types.NewBigInt(21000),
+		GasFee:            types.NewBigInt(63000),
+		EffectiveGasPrice: types.NewBigInt(3),
+		OutcomeStatus:     0,
+		TxExecErrorMsg:    &msg,
+		Space:             &space,
+	}
+
+	summary, err := AnalyzeReceipt(&receipt)
+	assert.NoError(t, err)
+	assert.Equal(t, "EVM_SPACE_FAIL", summary.OutcomeStatus)
+	assert.Equal(t, PayerSender, summary.GasPayer)
+	assert.Equal(t, big.NewInt(3), summary.EffectiveGasPrice)
+	assert.Equal(t, big.NewInt(63000), summary.TipFee)
+	assert.Equal(t, PayerNone, summary.StoragePayer)
+	assert.Empty(t, summary.StorageChanges)
+	assert.Equal(t, "denied", summary.ExecError.Reason)
+
+	receipt.OutcomeStatus = 0xff
+	summary, err = AnalyzeReceipt(&receipt)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.TRANSACTION_OUTCOME_SKIPPED, summary.Outcome)
+	assert.Equal(t, "EVM_SPACE_SKIPPED", summary.OutcomeStatus)
+	assert.Equal(t, PayerNone, summary.GasPayer)
+
+	receipt.OutcomeStatus = 3
+	_, err = AnalyzeReceipt(&receipt)
+	assert.Error(t, err)
+}
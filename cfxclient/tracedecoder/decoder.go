@@ -0,0 +1,432 @@
+// Package tracedecoder decodes the trace trees returned by types.TraceInTire with contract ABIs, so that each
+// call frame could be rendered as human readable contract.method(args) -> returns or revert reason.
+package tracedecoder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils/abiutil"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// Contract is a named contract ABI.
+type Contract struct {
+	Name string
+	ABI  *abi.ABI
+}
+
+// Registry is the ABI registry of contracts by address. Besides, fallback ABIs, e.g. ERC20, could be added
+// to decode calls to unregistered contracts by method selector.
+type Registry struct {
+	contracts map[common.Address]*Contract
+	fallbacks []*Contract
+}
+
+// NewRegistry creates an empty ABI registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		contracts: make(map[common.Address]*Contract),
+	}
+}
+
+// Register registers the ABI of contract with name at the specified address.
+func (r *Registry) Register(address types.Address, name string, contractABI *abi.ABI) {
+	r.contracts[address.MustGetCommonAddress()] = &Contract{name, contractABI}
+}
+
+// RegisterJSON registers the ABI of contract in JSON format with name at the specified address.
+func (r *Registry) RegisterJSON(address types.Address, name string, abiJSON string) error {
+	contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse ABI of %v", name)
+	}
+
+	r.Register(address, name, &contractABI)
+
+	return nil
+}
+
+// AddFallback adds the ABI to decode calls to unregistered contracts, which are tried in order of adding.
+func (r *Registry) AddFallback(name string, contractABI *abi.ABI) {
+	r.fallbacks = append(r.fallbacks, &Contract{name, contractABI})
+}
+
+// Contract returns the contract registered at the specified address, or nil if not registered.
+func (r *Registry) Contract(address types.Address) *Contract {
+	return r.contracts[address.MustGetCommonAddress()]
+}
+
+// lookupMethod returns the contract and method of the selector of contract at address.
+func (r *Registry) lookupMethod(address types.Address, selector []byte) (*Contract, *abi.Method) {
+	contract := r.Contract(address)
+	if contract != nil {
+		if method, err := contract.ABI.MethodById(selector); err == nil {
+			return contract, method
+		}
+
+		return contract, nil
+	}
+
+	for _, v := range r.fallbacks {
+		if method, err := v.ABI.MethodById(selector); err == nil {
+			return v, method
+		}
+	}
+
+	return nil, nil
+}
+
+// lookupError returns the custom error of the selector, which is declared in the contract at address or fallbacks.
+func (r *Registry) lookupError(address types.Address, selector [4]byte) *abi.Error {
+	if contract := r.Contract(address); contract != nil {
+		if customErr, err := contract.ABI.ErrorByID(selector); err == nil {
+			return customErr
+		}
+	}
+
+	for _, v := range r.fallbacks {
+		if customErr, err := v.ABI.ErrorByID(selector); err == nil {
+			return customErr
+		}
+	}
+
+	return nil
+}
+
+// Argument is a decoded argument or return value.
+type Argument struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Frame is a decoded call, create or internal transfer of trace tree.
+type Frame struct {
+	Type     types.TraceType   `json:"type"`
+	From     string            `json:"from"`
+	To       string            `json:"to,omitempty"`
+	Contract string            `json:"contract,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	Args     []Argument        `json:"args,omitempty"`
+	Returns  []Argument        `json:"returns,omitempty"`
+	Value    *hexutil.Big      `json:"value,omitempty"`
+	GasUsed  *uint64           `json:"gasUsed,omitempty"`
+	Outcome  types.OutcomeType `json:"outcome,omitempty"`
+	Error    string            `json:"error,omitempty"` // revert reason or failure
+	Failed   bool              `json:"failed"`
+
+	// raw data that could not be decoded
+	Input      hexutil.Bytes `json:"input,omitempty"`
+	ReturnData hexutil.Bytes `json:"returnData,omitempty"`
+
+	// internal transfer only
+	FromPocket types.PocketType `json:"fromPocket,omitempty"`
+	ToPocket   types.PocketType `json:"toPocket,omitempty"`
+
+	Children []*Frame `json:"children,omitempty"`
+}
+
+// Decoder decodes trace trees with ABI registry.
+type Decoder struct {
+	registry *Registry
+}
+
+// NewDecoder creates a decoder with the ABI registry, which could be nil to decode revert reasons only.
+func NewDecoder(registry *Registry) *Decoder {
+	if registry == nil {
+		registry = NewRegistry()
+	}
+
+	return &Decoder{registry}
+}
+
+// DecodeTransaction queries the traces of transaction and decodes them.
+func (d *Decoder) DecodeTransaction(trace sdk.RpcTrace, txHash types.Hash) ([]*Frame, error) {
+	traces, err := trace.GetTransactionTraces(txHash)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get traces of transaction %v", txHash)
+	}
+
+	tree, err := types.TraceInTire(traces)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to build trace tree of transaction %v", txHash)
+	}
+
+	return d.Decode(tree), nil
+}
+
+// Decode decodes the trace tree. Data that could not be decoded is kept as raw in frames.
+func (d *Decoder) Decode(tree types.LocalizedTraceTire) []*Frame {
+	frames := make([]*Frame, 0, len(tree))
+
+	for _, v := range tree {
+		if frame := d.DecodeNode(v); frame != nil {
+			frames = append(frames, frame)
+		}
+	}
+
+	return frames
+}
+
+// DecodeNode decodes the trace node and its children recursively.
+func (d *Decoder) DecodeNode(node *types.LocalizedTraceNode) *Frame {
+	var frame *Frame
+
+	switch {
+	case node.CallWithResult != nil && node.CallWithResult.Call != nil:
+		frame = d.decodeCall(node.CallWithResult)
+	case node.CreateWithResult != nil && node.CreateWithResult.Create != nil:
+		frame = d.decodeCreate(node.CreateWithResult)
+	case node.InternalTransferAction != nil:
+		action := node.InternalTransferAction
+		frame = &Frame{
+			From:       action.From.String(),
+			To:         action.To.String(),
+			Value:      &action.Value,
+			FromPocket: action.FromPocket,
+			ToPocket:   action.ToPocket,
+		}
+	default:
+		return nil
+	}
+
+	frame.Type = node.Type
+	if !node.Valid {
+		frame.Failed = true
+	}
+
+	for _, v := range node.Childs {
+		if child := d.DecodeNode(v); child != nil {
+			frame.Children = append(frame.Children, child)
+		}
+	}
+
+	return frame
+}
+
+func (d *Decoder) decodeCall(trace *types.TraceCallWithResult) *Frame {
+	call := trace.Call
+
+	frame := Frame{
+		From:  call.From.String(),
+		To:    call.To.String(),
+		Value: &call.Value,
+	}
+
+	if contract := d.registry.Contract(call.To); contract != nil {
+		frame.Contract = contract.Name
+	}
+
+	var method *abi.Method
+
+	if len(call.Input) == 0 {
+		frame.Method = "receive"
+	} else if len(call.Input) < 4 {
+		frame.Method = "fallback"
+		frame.Input = call.Input
+	} else {
+		var contract *Contract
+		contract, method = d.registry.lookupMethod(call.To, call.Input[:4])
+
+		if contract != nil {
+			frame.Contract = contract.Name
+		}
+
+		if method == nil {
+			frame.Method = hexutil.Encode(call.Input[:4])
+			frame.Input = call.Input
+		} else if args, err := decodeArguments(method.Inputs, call.Input[4:]); err != nil {
+			frame.Method = method.Name
+			frame.Input = call.Input
+		} else {
+			frame.Method = method.Name
+			frame.Args = args
+		}
+	}
+
+	result := trace.CallResult
+	if result == nil {
+		return &frame
+	}
+
+	frame.Outcome = result.Outcome
+	frame.GasUsed = gasUsed(&call.Gas, &result.GasLeft)
+
+	if result.Outcome != types.OUTCOME_SUCCESS {
+		frame.Failed = true
+		frame.Error = d.decodeFailure(call.To, result.Outcome, result.ReturnData)
+		return &frame
+	}
+
+	if len(result.ReturnData) == 0 {
+		return &frame
+	}
+
+	if method != nil {
+		if returns, err := decodeArguments(method.Outputs, result.ReturnData); err == nil {
+			frame.Returns = returns
+			return &frame
+		}
+	}
+
+	frame.ReturnData = result.ReturnData
+
+	return &frame
+}
+
+func (d *Decoder) decodeCreate(trace *types.TraceCreateWithResult) *Frame {
+	create := trace.Create
+
+	frame := Frame{
+		From:   create.From.String(),
+		Method: "constructor",
+		Value:  &create.Value,
+	}
+
+	result := trace.CreateResult
+	if result == nil {
+		return &frame
+	}
+
+	frame.To = result.Addr.String()
+	if contract := d.registry.Contract(result.Addr); contract != nil {
+		frame.Contract = contract.Name
+	}
+
+	frame.Outcome = result.Outcome
+	frame.GasUsed = gasUsed(&create.Gas, &result.GasLeft)
+
+	// return data of successful create is the deployed code
+	if result.Outcome != types.OUTCOME_SUCCESS {
+		frame.Failed = true
+		frame.Error = d.decodeFailure(result.Addr, result.Outcome, result.ReturnData)
+	}
+
+	return &frame
+}
+
+// decodeFailure decodes the revert reason in return data, which could be Error(string), Panic(uint256) or
+// custom error declared in ABI.
+func (d *Decoder) decodeFailure(address types.Address, outcome types.OutcomeType, data []byte) string {
+	if outcome != types.OUTCOME_REVERTED {
+		return string(outcome)
+	}
+
+	if len(data) < 4 {
+		return "reverted"
+	}
+
+	if string(data[:4]) == string(errorSelector) {
+		if reason, err := abiutil.DecodeErrData(data); err == nil {
+			return fmt.Sprintf("reverted: %v", reason)
+		}
+	}
+
+	if string(data[:4]) == string(panicSelector) && len(data) == 36 {
+		code := new(big.Int).SetBytes(data[4:])
+		return fmt.Sprintf("panic: 0x%x", code)
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+
+	if customErr := d.registry.lookupError(address, selector); customErr != nil {
+		if args, err := decodeArguments(customErr.Inputs, data[4:]); err == nil {
+			return fmt.Sprintf("reverted: %v(%v)", customErr.Name, formatArguments(args))
+		}
+	}
+
+	return fmt.Sprintf("reverted: %v", hexutil.Encode(data))
+}
+
+func decodeArguments(arguments abi.Arguments, data []byte) ([]Argument, error) {
+	values, err := arguments.UnpackValues(data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]Argument, len(values))
+	for i, v := range values {
+		result[i] = Argument{
+			Name:  arguments[i].Name,
+			Type:  arguments[i].Type.String(),
+			Value: formatValue(v),
+		}
+	}
+
+	return result, nil
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	}
+
+	// fixed size bytes, e.g. bytes32
+	if bytes, ok := fixedBytes(value); ok {
+		return hexutil.Encode(bytes)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func fixedBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case [4]byte:
+		return v[:], true
+	case [8]byte:
+		return v[:], true
+	case [16]byte:
+		return v[:], true
+	case [20]byte:
+		return v[:], true
+	case [32]byte:
+		return v[:], true
+	}
+
+	return nil, false
+}
+
+func formatArguments(args []Argument) string {
+	formatted := make([]string, len(args))
+
+	for i, v := range args {
+		if v.Name == "" {
+			formatted[i] = v.Value
+		} else {
+			formatted[i] = fmt.Sprintf("%v=%v", v.Name, v.Value)
+		}
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func gasUsed(gas, gasLeft *hexutil.Big) *uint64 {
+	used := new(big.Int).Sub(gas.ToInt(), gasLeft.ToInt())
+	if used.Sign() < 0 || !used.IsUint64() {
+		return nil
+	}
+
+	value := used.Uint64()
+
+	return &value
+}
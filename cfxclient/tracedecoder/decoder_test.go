@@ -0,0 +1,137 @@
+package tracedecoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const tokenABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"}]}
+]`
+
+var (
+	user  = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000001", 1029)
+	token = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000002", 1029)
+	other = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000003", 1029)
+)
+
+func newTestDecoder(t *testing.T) (*Decoder, abi.ABI) {
+	registry := NewRegistry()
+	assert.NoError(t, registry.RegisterJSON(token, "Token", tokenABI))
+
+	parsed, err := abi.JSON(strings.NewReader(tokenABI))
+	assert.NoError(t, err)
+
+	return NewDecoder(registry), parsed
+}
+
+func newCall(from, to types.Address, gas int64, input []byte, valid bool) types.LocalizedTrace {
+	return types.LocalizedTrace{
+		Type:  types.TRACE_CALL,
+		Valid: valid,
+		Action: types.Call{
+			Space: types.SPACE_NATIVE, From: from, To: to, Input: input,
+			Gas: hexutil.Big(*big.NewInt(gas)), Value: hexutil.Big(*big.NewInt(0)),
+		},
+	}
+}
+
+func newCallResult(outcome types.OutcomeType, gasLeft int64, data []byte, valid bool) types.LocalizedTrace {
+	return types.LocalizedTrace{
+		Type:   types.TRACE_CALL_RESULT,
+		Valid:  valid,
+		Action: types.CallResult{Outcome: outcome, GasLeft: hexutil.Big(*big.NewInt(gasLeft)), ReturnData: data},
+	}
+}
+
+func TestDecode(t *testing.T) {
+	decoder, parsed := newTestDecoder(t)
+
+	recipient := common.HexToAddress("0x1000000000000000000000000000000000000009")
+	input, err := parsed.Pack("transfer", recipient, big.NewInt(100))
+	assert.NoError(t, err)
+
+	output, err := parsed.Methods["transfer"].Outputs.Pack(true)
+	assert.NoError(t, err)
+
+	// Error(string) with reason "denied"
+	revertData := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000006" +
+		"64656e6965640000000000000000000000000000000000000000000000000000")
+
+	customErr, err := parsed.Errors["InsufficientBalance"].Inputs.Pack(big.NewInt(5))
+	assert.NoError(t, err)
+	errID := parsed.Errors["InsufficientBalance"].ID
+	customErr = append(errID[:4], customErr...)
+
+	tree, err := types.TraceInTire([]types.LocalizedTrace{
+		newCall(user, token, 50000, input, true),
+		newCall(token, other, 10000, []byte{0x12, 0x34, 0x56, 0x78}, false),
+		newCallResult(types.OUTCOME_REVERTED, 4000, revertData, false),
+		newCall(token, token, 8000, input, false),
+		newCallResult(types.OUTCOME_REVERTED, 7000, customErr, false),
+		newCallResult(types.OUTCOME_SUCCESS, 20000, output, true),
+	})
+	assert.NoError(t, err)
+
+	frames := decoder.Decode(tree)
+	assert.Equal(t, 1, len(frames))
+
+	root := frames[0]
+	assert.Equal(t, "Token", root.Contract)
+	assert.Equal(t, "transfer", root.Method)
+	assert.Equal(t, []Argument{
+		{"to", "address", recipient.Hex()},
+		{"amount", "uint256", "100"},
+	}, root.Args)
+	assert.Equal(t, []Argument{{"", "bool", "true"}}, root.Returns)
+	assert.Equal(t, uint64(30000), *root.GasUsed)
+	assert.False(t, root.Failed)
+	assert.Equal(t, 2, len(root.Children))
+
+	unknown := root.Children[0]
+	assert.True(t, unknown.Failed)
+	assert.Equal(t, "0x12345678", unknown.Method)
+	assert.Equal(t, "reverted: denied", unknown.Error)
+	assert.Equal(t, uint64(6000), *unknown.GasUsed)
+
+	assert.Equal(t, "reverted: InsufficientBalance(available=5)", root.Children[1].Error)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteText(&buf, frames))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "[call] Token("+token.String()+").transfer(to="+recipient.Hex()+", amount=100) -> (true) gas=30000", lines[0])
+	assert.Equal(t, "  [call] FAILED "+other.String()+".0x12345678() input=0x12345678 gas=6000 reverted: denied", lines[1])
+
+	buf.Reset()
+	assert.NoError(t, WriteJSON(&buf, frames))
+
+	var decoded []*Frame
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "transfer", decoded[0].Method)
+	assert.True(t, decoded[0].Children[0].Failed)
+}
+
+func TestDecodeFailure(t *testing.T) {
+	decoder := NewDecoder(nil)
+
+	panicData := append([]byte{0x4e, 0x48, 0x7b, 0x71}, common.LeftPadBytes([]byte{0x11}, 32)...)
+	assert.Equal(t, "panic: 0x11", decoder.decodeFailure(token, types.OUTCOME_REVERTED, panicData))
+	assert.Equal(t, "reverted", decoder.decodeFailure(token, types.OUTCOME_REVERTED, nil))
+	assert.Equal(t, "fail", decoder.decodeFailure(token, types.OUTCOME_FAIL, nil))
+	assert.Equal(t, "reverted: 0x01020304", decoder.decodeFailure(token, types.OUTCOME_REVERTED, []byte{1, 2, 3, 4}))
+}
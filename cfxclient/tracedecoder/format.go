@@ -0,0 +1,105 @@
+package tracedecoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// String renders the frame in one line, e.g.
+//
+//	[call] Token(cfx:...).transfer(to=0x..., amount=100) -> (true) gas=21000
+//	[call] FAILED Token(cfx:...).transfer(to=0x..., amount=100) gas=3000 reverted: insufficient balance
+func (f *Frame) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "[%v] ", f.Type)
+
+	if f.Failed {
+		sb.WriteString("FAILED ")
+	}
+
+	if f.FromPocket != "" || f.ToPocket != "" {
+		fmt.Fprintf(&sb, "%v(%v) -> %v(%v) value=%v", f.From, f.FromPocket, f.To, f.ToPocket, f.Value.ToInt())
+		return sb.String()
+	}
+
+	target := f.To
+	if target == "" {
+		target = "<unknown>"
+	}
+
+	if f.Contract != "" {
+		target = fmt.Sprintf("%v(%v)", f.Contract, target)
+	}
+
+	if f.Method == "constructor" {
+		fmt.Fprintf(&sb, "new %v", target)
+	} else {
+		fmt.Fprintf(&sb, "%v.%v(%v)", target, f.Method, formatArguments(f.Args))
+	}
+
+	if f.Value != nil && f.Value.ToInt().Sign() > 0 {
+		fmt.Fprintf(&sb, "{value: %v}", f.Value.ToInt())
+	}
+
+	if len(f.Input) > 0 {
+		fmt.Fprintf(&sb, " input=%v", f.Input)
+	}
+
+	if len(f.Returns) > 0 {
+		fmt.Fprintf(&sb, " -> (%v)", formatArguments(f.Returns))
+	} else if len(f.ReturnData) > 0 {
+		fmt.Fprintf(&sb, " -> %v", f.ReturnData)
+	}
+
+	if f.GasUsed != nil {
+		fmt.Fprintf(&sb, " gas=%v", *f.GasUsed)
+	}
+
+	if f.Error != "" {
+		fmt.Fprintf(&sb, " %v", f.Error)
+	}
+
+	return sb.String()
+}
+
+// WriteText writes the frames as text tree, one frame per line and children are indented.
+func WriteText(w io.Writer, frames []*Frame) error {
+	for _, v := range frames {
+		if err := writeText(w, v, 0); err != nil {
+			return errors.Wrap(err, "failed to write frame")
+		}
+	}
+
+	return nil
+}
+
+func writeText(w io.Writer, frame *Frame, depth int) error {
+	if _, err := fmt.Fprintf(w, "%v%v\n", strings.Repeat("  ", depth), frame); err != nil {
+		return err
+	}
+
+	for _, v := range frame.Children {
+		if err := writeText(w, v, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes the frames in JSON format.
+func WriteJSON(w io.Writer, frames []*Frame) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(frames); err != nil {
+		return errors.Wrap(err, "failed to encode frames in JSON")
+	}
+
+	return nil
+}
@@ -6,12 +6,12 @@ package sdk
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"sync/atomic"
 
 	"math/big"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/Conflux-Chain/go-conflux-sdk/constants"
@@ -27,6 +27,7 @@ import (
 	"github.com/Conflux-Chain/go-conflux-sdk/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 )
 
@@ -48,6 +49,12 @@ type Client struct {
 	rpcTxpoolClient RpcTxpoolClient
 	rpcDebugClient  RpcDebugClient
 	rpcFilterClient RpcFilterClient
+	rpcESpaceClient *RpcESpaceClient
+
+	feeOracle FeeOracle
+	// outbox is shared with the copies of client, e.g. the one held by BulkSender, so that the outbox set
+	// later by SetOutbox is used by them too
+	outbox *atomic.Pointer[Outbox]
 }
 
 // ClientOption for set keystore path and flags for retry and timeout
@@ -66,6 +73,12 @@ type ClientOption struct {
 	Logger io.Writer
 
 	CircuitBreakerOption *providers.DefaultCircuitBreakerOption
+
+	// ESpaceNodeURL is the url of eSpace node, the eSpace client is accessible by Client.ESpace() if it is not empty
+	ESpaceNodeURL string
+
+	// FeeStrategy suggests gas fee of transactions, the DefaultFeeStrategy is used if it is nil
+	FeeStrategy FeeStrategy
 }
 
 // NewClient creates an instance of Client with specified conflux node url, it will creat account manager if option.KeystorePath not empty.
@@ -124,9 +137,18 @@ func NewClientWithRPCRequester(provider RpcRequester) (*Client, error) {
 
 // NewClientWithProvider creates an instance of Client with specified provider, and will wrap it to create a MiddlewarableProvider for be able to hooking CallContext/BatchCallContext/Subscribe
 func NewClientWithProvider(provider interfaces.Provider) (*Client, error) {
-	return &Client{
+	client := &Client{
 		MiddlewarableProvider: providers.NewMiddlewarableProvider(provider),
-	}, nil
+	}
+	client.rpcPosClient = RpcPosClient{client}
+	client.rpcTxpoolClient = RpcTxpoolClient{client}
+	client.rpcDebugClient = RpcDebugClient{client}
+	client.rpcFilterClient = RpcFilterClient{client}
+	client.RpcTraceClient = RpcTraceClient{client}
+	client.feeOracle = NewFeeOracle(client)
+	client.outbox = new(atomic.Pointer[Outbox])
+
+	return client, nil
 }
 
 // NewClientWithRetry creates a retryable new instance of Client with specified conflux node url and retry options.
@@ -145,6 +167,8 @@ func newClientWithOption(nodeURL string, clientOption ClientOption) (*Client, er
 	client.rpcDebugClient = RpcDebugClient{&client}
 	client.rpcFilterClient = RpcFilterClient{&client}
 	client.RpcTraceClient = RpcTraceClient{&client}
+	client.feeOracle = NewFeeOracle(&client)
+	client.outbox = new(atomic.Pointer[Outbox])
 
 	p, err := providers.NewProviderWithOption(nodeURL, *clientOption.genProviderOption())
 	if err != nil {
@@ -168,6 +192,13 @@ func newClientWithOption(nodeURL string, clientOption ClientOption) (*Client, er
 		return nil, errors.Wrap(err, "failed to get chainID")
 	}
 
+	if client.option.ESpaceNodeURL != "" {
+		client.rpcESpaceClient, err = NewRpcESpaceClient(&client, client.option.ESpaceNodeURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to new eSpace client")
+		}
+	}
+
 	return &client, nil
 }
 
@@ -213,6 +244,11 @@ func (client *Client) Trace() RpcTrace {
 	return &client.RpcTraceClient
 }
 
+// ESpace returns RpcESpaceClient for invoke eSpace rpc, it returns nil if ClientOption.ESpaceNodeURL is not specified
+func (client *Client) ESpace() *RpcESpaceClient {
+	return client.rpcESpaceClient
+}
+
 // GetNodeURL returns node url
 func (client *Client) GetNodeURL() string {
 	return client.nodeURL
@@ -304,6 +340,24 @@ func (client *Client) SetAccountManager(accountManager AccountManagerOperator) {
 	client.AccountManager = accountManager
 }
 
+// SetOutbox sets the outbox to record the transactions sent by SendTransaction and BulkSender.
+func (client *Client) SetOutbox(outbox *Outbox) {
+	if client.outbox == nil {
+		client.outbox = new(atomic.Pointer[Outbox])
+	}
+
+	client.outbox.Store(outbox)
+}
+
+// Outbox returns the outbox set by SetOutbox, and nil if not set.
+func (client *Client) Outbox() *Outbox {
+	if client.outbox == nil {
+		return nil
+	}
+
+	return client.outbox.Load()
+}
+
 // GetGasPrice returns the recent mean gas price.
 func (client *Client) GetGasPrice() (gasPrice *hexutil.Big, err error) {
 	err = client.wrappedCallRPC(&gasPrice, "cfx_gasPrice")
@@ -484,8 +538,22 @@ func (client *Client) SendTransaction(tx types.UnsignedTransaction) (types.Hash,
 		return "", errors.Wrap(err, "failed to sign transaction")
 	}
 
+	outbox := client.Outbox()
+	if outbox != nil {
+		if _, err := outbox.Record(tx, rawData); err != nil {
+			return "", errors.Wrap(err, "failed to record transaction to outbox")
+		}
+	}
+
 	//send raw tx
 	txhash, err := client.SendRawTransaction(rawData)
+
+	if outbox != nil {
+		if e := outbox.MarkSent(RawTxHash(rawData), err); e != nil {
+			return "", errors.Wrap(e, "failed to update transaction in outbox")
+		}
+	}
+
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to send transaction, raw data = 0x%+x", rawData)
 	}
@@ -506,6 +574,16 @@ func (client *Client) SendRawTransaction(rawData []byte) (hash types.Hash, err e
 	return
 }
 
+// RawTxHash returns the hash of signed transaction, which is the keccak256 hash of rlp encoded data.
+func RawTxHash(rawData []byte) types.Hash {
+	return types.Hash(hexutil.Encode(crypto.Keccak256(rawData)))
+}
+
+// IsTxAlreadyExistError returns true if the transaction sent already exists in txpool of node.
+func IsTxAlreadyExistError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "tx already exist")
+}
+
 // SignEncodedTransactionAndSend signs RLP encoded transaction "encodedTx" by signature "r,s,v" and sends it to node,
 // and returns responsed transaction.
 func (client *Client) SignEncodedTransactionAndSend(encodedTx []byte, v byte, r, s []byte) (*types.Transaction, error) {
@@ -861,103 +939,33 @@ func (client *Client) populateTxtypeAndGasPrice(tx *types.UnsignedTransaction) e
 		return nil
 	}
 
-	has1559 := tx.MaxFeePerGas != nil || tx.MaxPriorityFeePerGas != nil
-
-	gasFeeData, err := client.getFeeData()
+	fee, err := client.SuggestFee()
 	if err != nil {
 		return errors.Wrap(err, "failed to get fee data")
 	}
 
-	// set the txtype according to feeData
-	// - if support1559, then set txtype to 2
-	// - if not support1559
-	// - - if has maxFeePerGas or maxPriorityFeePerGas, then return error
-	// - - if contains accesslist, set txtype to 1
-	// - - else set txtype to 0
-	if tx.Type == nil {
-		if gasFeeData.isSupport1559() {
-			tx.Type = types.TRANSACTION_TYPE_1559.Ptr()
-		} else {
-			if has1559 {
-				return errors.New("not support 1559 but (maxFeePerGas or maxPriorityFeePerGas) specified")
-			}
-
-			if tx.AccessList == nil {
-				tx.Type = types.TRANSACTION_TYPE_LEGACY.Ptr()
-			} else {
-				tx.Type = types.TRANSACTION_TYPE_2930.Ptr()
-			}
-		}
-	}
-
-	// if txtype is DynamicFeeTxType that means support 1559, so if gasPrice is not nil, set max... to gasPrice
-	if *tx.Type == types.TRANSACTION_TYPE_1559 {
-		if tx.GasPrice != nil {
-			tx.MaxFeePerGas = tx.GasPrice
-			tx.MaxPriorityFeePerGas = tx.GasPrice
-			tx.GasPrice = nil
-			return nil
-		}
-
-		if tx.MaxPriorityFeePerGas == nil {
-			tx.MaxPriorityFeePerGas = (*hexutil.Big)(gasFeeData.maxPriorityFeePerGas)
-		}
-		if tx.MaxFeePerGas == nil {
-			tx.MaxFeePerGas = (*hexutil.Big)(gasFeeData.maxFeePerGas)
-		}
-		if tx.MaxFeePerGas.ToInt().Cmp(tx.MaxPriorityFeePerGas.ToInt()) < 0 {
-			return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", tx.MaxFeePerGas, tx.MaxPriorityFeePerGas)
-		}
-		return nil
-	}
-
-	if tx.GasPrice != nil {
-		return nil
-	}
-
-	tx.GasPrice = (*hexutil.Big)(gasFeeData.gasPrice)
-	return nil
-}
-
-type gasFeeData struct {
-	gasPrice             *hexutil.Big
-	maxFeePerGas         *hexutil.Big
-	maxPriorityFeePerGas *hexutil.Big
-}
-
-func (g gasFeeData) isSupport1559() bool {
-	return g.maxPriorityFeePerGas != nil && g.maxFeePerGas != nil
+	return ApplyFeeData(tx, fee)
 }
 
-func (client *Client) getFeeData() (*gasFeeData, error) {
-	data := &gasFeeData{}
-
-	gasPrice, err := client.GetGasPrice()
-	if err != nil {
-		return nil, err
-	}
-	data.gasPrice = gasPrice
-
-	block, err := client.GetBlockByEpoch(types.EpochLatestState)
-	if err != nil {
-		return nil, err
+// SuggestFee returns the gas fee suggested by ClientOption.FeeStrategy, or DefaultFeeStrategy if not set.
+// The fee market data is cached per latest state epoch.
+func (client *Client) SuggestFee() (*FeeData, error) {
+	strategy := client.option.FeeStrategy
+	if strategy == nil {
+		strategy = DefaultFeeStrategy{}
 	}
-	basefee := block.BaseFeePerGas
 
-	if basefee == nil {
-		return data, nil
-	}
+	return strategy.SuggestFee(client.getFeeOracle())
+}
 
-	priorityFeePerGas, err := client.GetMaxPriorityFeePerGas()
-	if err != nil {
-		return nil, err
+// getFeeOracle returns the fee oracle created by constructors, or a new one without cache if the client is
+// not created by constructors.
+func (client *Client) getFeeOracle() FeeOracle {
+	if client.feeOracle == nil {
+		return NewFeeOracle(client)
 	}
 
-	data.maxPriorityFeePerGas = priorityFeePerGas
-	maxFeePerGas := new(big.Int).Mul(basefee.ToInt(), big.NewInt(2))
-	maxFeePerGas = new(big.Int).Add(maxFeePerGas, data.maxPriorityFeePerGas.ToInt())
-	data.maxFeePerGas = types.NewBigIntByRaw(maxFeePerGas)
-	return data, nil
+	return client.feeOracle
 }
 
 // DeployContract deploys a contract by abiJSON, bytecode and consturctor params.
@@ -0,0 +1,202 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	rpc "github.com/openweb3/go-rpc-provider"
+	providers "github.com/openweb3/go-rpc-provider/provider_wrapper"
+	"github.com/openweb3/web3go"
+	web3gotypes "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	methodEthSendTransaction    = "eth_sendTransaction"
+	methodEthSendRawTransaction = "eth_sendRawTransaction"
+)
+
+// RpcESpaceClient used to access eSpace RPC of Conflux blockchain. It is a web3go client which uses the same
+// ClientOption as the core space client, and signs eSpace transactions by the AccountManager of core space client
+// if the account manager implements ESpaceSigner.
+type RpcESpaceClient struct {
+	*web3go.Client
+	core *Client
+}
+
+// NewRpcESpaceClient creates a new RpcESpaceClient instance connecting to eSpace node url with the option of core
+func NewRpcESpaceClient(core *Client, nodeURL string) (*RpcESpaceClient, error) {
+	p, err := providers.NewProviderWithOption(nodeURL, *core.option.genProviderOption())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to new eSpace provider")
+	}
+
+	c := &RpcESpaceClient{core: core}
+	p.HookCallContext(c.signableCallContextMiddleware)
+	p.HookBatchCallContext(c.signableBatchCallContextMiddleware)
+	c.Client = web3go.NewClientWithProvider(p)
+	return c, nil
+}
+
+// SignTransaction populates the default values of tx and signs it by the account manager of core space client
+func (c *RpcESpaceClient) SignTransaction(tx web3gotypes.TransactionArgs) (*ethtypes.Transaction, error) {
+	signer, err := c.getESpaceSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := c.getFrom(signer, tx.From)
+	if err != nil {
+		return nil, err
+	}
+	tx.From = &from
+
+	if err = tx.Populate(c.Eth); err != nil {
+		return nil, errors.Wrap(err, errMsgApplyTxValues)
+	}
+
+	return c.signTransaction(signer, tx)
+}
+
+// SendTransaction signs tx by the account manager of core space client and sends it
+func (c *RpcESpaceClient) SendTransaction(tx web3gotypes.TransactionArgs) (common.Hash, error) {
+	signed, err := c.SignTransaction(tx)
+	if err != nil {
+		return common.Hash{}, errors.WithMessage(err, "failed to sign transaction")
+	}
+
+	rawTx, err := signed.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to encode transaction")
+	}
+
+	return c.Eth.SendRawTransaction(rawTx)
+}
+
+func (c *RpcESpaceClient) getESpaceSigner() (ESpaceSigner, error) {
+	if c.core.AccountManager == nil {
+		return nil, errors.New("account manager not specified, see SetAccountManager")
+	}
+
+	signer, ok := c.core.AccountManager.(ESpaceSigner)
+	if !ok {
+		return nil, errors.New("account manager is not able to sign eSpace transaction")
+	}
+	return signer, nil
+}
+
+func (c *RpcESpaceClient) getFrom(signer ESpaceSigner, from *common.Address) (common.Address, error) {
+	accounts := signer.ListESpace()
+	if from == nil {
+		if len(accounts) == 0 {
+			return common.Address{}, errors.New("no account found")
+		}
+		return accounts[0], nil
+	}
+
+	for _, account := range accounts {
+		if account == *from {
+			return account, nil
+		}
+	}
+	return common.Address{}, errors.Errorf("account %v not found", from)
+}
+
+func (c *RpcESpaceClient) signTransaction(signer ESpaceSigner, tx web3gotypes.TransactionArgs) (*ethtypes.Transaction, error) {
+	chainID, err := c.Eth.ChainId()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get chain id")
+	}
+
+	unsigned, err := tx.ToTransaction()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert to transaction")
+	}
+
+	return signer.SignESpaceTransaction(*tx.From, unsigned, new(big.Int).SetUint64(*chainID))
+}
+
+// signRawIfPossible signs the eth_sendTransaction argument by the account manager, it returns false if the
+// account manager is not able to sign it, then the request will be sent to node directly.
+func (c *RpcESpaceClient) signRawIfPossible(arg interface{}) (hexutil.Bytes, bool, error) {
+	signer, err := c.getESpaceSigner()
+	if err != nil {
+		return nil, false, nil
+	}
+
+	var tx web3gotypes.TransactionArgs
+	switch v := arg.(type) {
+	case web3gotypes.TransactionArgs:
+		tx = v
+	case *web3gotypes.TransactionArgs:
+		tx = *v
+	default:
+		j, err := json.Marshal(arg)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to marshal transaction args")
+		}
+		if err = json.Unmarshal(j, &tx); err != nil {
+			return nil, false, errors.Wrap(err, "failed to unmarshal transaction args")
+		}
+	}
+
+	from, err := c.getFrom(signer, tx.From)
+	if err != nil {
+		return nil, false, nil
+	}
+	tx.From = &from
+
+	if err = tx.Populate(c.Eth); err != nil {
+		return nil, false, errors.Wrap(err, errMsgApplyTxValues)
+	}
+
+	signed, err := c.signTransaction(signer, tx)
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "failed to sign transaction")
+	}
+
+	rawTx, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to encode transaction")
+	}
+	return rawTx, true, nil
+}
+
+func (c *RpcESpaceClient) signableCallContextMiddleware(call providers.CallContextFunc) providers.CallContextFunc {
+	return func(ctx context.Context, resultPtr interface{}, method string, args ...interface{}) error {
+		if method == methodEthSendTransaction && len(args) > 0 {
+			rawTx, ok, err := c.signRawIfPossible(args[0])
+			if err != nil {
+				return err
+			}
+			if ok {
+				return call(ctx, resultPtr, methodEthSendRawTransaction, rawTx)
+			}
+		}
+		return call(ctx, resultPtr, method, args...)
+	}
+}
+
+func (c *RpcESpaceClient) signableBatchCallContextMiddleware(batchCall providers.BatchCallContextFunc) providers.BatchCallContextFunc {
+	return func(ctx context.Context, b []rpc.BatchElem) error {
+		for i := range b {
+			if b[i].Method != methodEthSendTransaction || len(b[i].Args) == 0 {
+				continue
+			}
+
+			rawTx, ok, err := c.signRawIfPossible(b[i].Args[0])
+			if err != nil {
+				return err
+			}
+			if ok {
+				b[i].Method = methodEthSendRawTransaction
+				b[i].Args = []interface{}{rawTx}
+			}
+		}
+		return batchCall(ctx, b)
+	}
+}
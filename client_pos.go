@@ -5,6 +5,7 @@ import (
 	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
 	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
 )
 
 // RpcPosClient used to access pos namespace RPC of Conflux blockchain.
@@ -110,3 +111,125 @@ func (c *RpcPosClient) GetLedgerInfosByEpoch(startEpoch hexutil.Uint64, endEpoch
 	err = c.core.CallRPC(&ledgerInfoWithSigs, "pos_getLedgerInfosByEpoch", startEpoch, endEpoch)
 	return
 }
+
+// PosTransactionIterator iterates over PoS transactions in a block range, e.g.
+//
+//	iter := sdk.NewPosTransactionIterator(client.Pos(), 100, 200)
+//	for iter.Next() {
+//		switch payload := iter.Transaction().Payload.Value().(type) {
+//		case *postypes.ElectionPayload:
+//			// handle election
+//		case *postypes.RetirePayload:
+//			// handle retire
+//		}
+//	}
+//
+//	if err := iter.Err(); err != nil {
+//		// handle error
+//	}
+type PosTransactionIterator struct {
+	pos       RpcPos
+	fromBlock uint64
+	toBlock   uint64
+
+	initialized bool
+	next        uint64 // next transaction number to retrieve
+	last        uint64 // last transaction number in block range
+
+	tx  *postypes.Transaction
+	err error
+}
+
+// NewPosTransactionIterator creates a new iterator over PoS transactions in block range [fromBlock, toBlock].
+func NewPosTransactionIterator(pos RpcPos, fromBlock, toBlock uint64) *PosTransactionIterator {
+	return &PosTransactionIterator{
+		pos:       pos,
+		fromBlock: fromBlock,
+		toBlock:   toBlock,
+	}
+}
+
+// Next retrieves the next transaction, and returns false if no more transaction or any error occurred.
+func (iter *PosTransactionIterator) Next() bool {
+	if iter.err != nil {
+		return false
+	}
+
+	if !iter.initialized {
+		if iter.err = iter.init(); iter.err != nil {
+			return false
+		}
+
+		iter.initialized = true
+	}
+
+	if iter.next > iter.last {
+		iter.tx = nil
+		return false
+	}
+
+	tx, err := iter.pos.GetTransactionByNumber(hexutil.Uint64(iter.next))
+	if err != nil {
+		iter.err = errors.WithMessagef(err, "failed to get PoS transaction by number %v", iter.next)
+		return false
+	}
+
+	if tx == nil {
+		iter.err = errors.Errorf("PoS transaction %v not found", iter.next)
+		return false
+	}
+
+	iter.tx = tx
+	iter.next++
+
+	return true
+}
+
+// Transaction returns the current transaction retrieved by Next.
+func (iter *PosTransactionIterator) Transaction() *postypes.Transaction {
+	return iter.tx
+}
+
+// Err returns the error occurred during iteration if any.
+func (iter *PosTransactionIterator) Err() error {
+	return iter.err
+}
+
+// init initializes the transaction number range of blocks, in which transactions of block N
+// are in range (LastTxNumber of block N-1, LastTxNumber of block N].
+func (iter *PosTransactionIterator) init() error {
+	if iter.fromBlock > iter.toBlock {
+		return errors.Errorf("invalid block range [%v, %v]", iter.fromBlock, iter.toBlock)
+	}
+
+	if iter.fromBlock > 0 {
+		prev, err := iter.getBlock(iter.fromBlock - 1)
+		if err != nil {
+			return err
+		}
+
+		iter.next = uint64(prev.LastTxNumber) + 1
+	}
+
+	block, err := iter.getBlock(iter.toBlock)
+	if err != nil {
+		return err
+	}
+
+	iter.last = uint64(block.LastTxNumber)
+
+	return nil
+}
+
+func (iter *PosTransactionIterator) getBlock(number uint64) (*postypes.Block, error) {
+	block, err := iter.pos.GetBlockByNumber(postypes.NewBlockNumber(number))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get PoS block by number %v", number)
+	}
+
+	if block == nil {
+		return nil, errors.Errorf("PoS block %v not found", number)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"testing"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePos struct {
+	RpcPos
+	lastTxNumbers []uint64 // last transaction number of blocks
+}
+
+func (p *fakePos) GetBlockByNumber(blockNumber postypes.BlockNumber) (*postypes.Block, error) {
+	var number hexutil.Uint64
+	if err := number.UnmarshalText([]byte(blockNumber.String())); err != nil {
+		return nil, err
+	}
+
+	if uint64(number) >= uint64(len(p.lastTxNumbers)) {
+		return nil, nil
+	}
+
+	return &postypes.Block{
+		Height:       number,
+		LastTxNumber: hexutil.Uint64(p.lastTxNumbers[number]),
+	}, nil
+}
+
+func (p *fakePos) GetTransactionByNumber(txNumber hexutil.Uint64) (*postypes.Transaction, error) {
+	if uint64(txNumber) > p.lastTxNumbers[len(p.lastTxNumbers)-1] {
+		return nil, nil
+	}
+
+	return &postypes.Transaction{Number: txNumber}, nil
+}
+
+func collectPosTransactions(iter *PosTransactionIterator) (numbers []uint64) {
+	for iter.Next() {
+		numbers = append(numbers, uint64(iter.Transaction().Number))
+	}
+
+	return
+}
+
+func TestPosTransactionIterator(t *testing.T) {
+	pos := &fakePos{lastTxNumbers: []uint64{0, 2, 2, 5}}
+
+	iter := NewPosTransactionIterator(pos, 0, 3)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5}, collectPosTransactions(iter))
+	assert.NoError(t, iter.Err())
+
+	iter = NewPosTransactionIterator(pos, 1, 1)
+	assert.Equal(t, []uint64{1, 2}, collectPosTransactions(iter))
+	assert.NoError(t, iter.Err())
+
+	// no transaction in block
+	iter = NewPosTransactionIterator(pos, 2, 2)
+	assert.Empty(t, collectPosTransactions(iter))
+	assert.NoError(t, iter.Err())
+
+	// block not found
+	iter = NewPosTransactionIterator(pos, 3, 4)
+	assert.Empty(t, collectPosTransactions(iter))
+	assert.Error(t, iter.Err())
+
+	// invalid range
+	iter = NewPosTransactionIterator(pos, 3, 2)
+	assert.False(t, iter.Next())
+	assert.Error(t, iter.Err())
+}
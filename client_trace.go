@@ -1,6 +1,15 @@
 package sdk
 
-import "github.com/Conflux-Chain/go-conflux-sdk/types"
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
 
 type RpcTraceClient struct {
 	core *Client
@@ -33,3 +42,325 @@ func (c *RpcTraceClient) GetEpochTraces(epoch types.Epoch) (traces types.EpochTr
 	err = c.core.wrappedCallRPC(&traces, "trace_epoch", epoch)
 	return
 }
+
+// FilterTracesIter returns an iterator that streams all traces matching the provided filter, and the epoch tags,
+// e.g. latest_state, of filter are resolved to epoch numbers at first. See NewTraceIterator for more details.
+func (c *RpcTraceClient) FilterTracesIter(ctx context.Context, traceFilter types.TraceFilter, option ...TraceIteratorOption) (*TraceIterator, error) {
+	for _, epoch := range []**types.Epoch{&traceFilter.FromEpoch, &traceFilter.ToEpoch} {
+		if *epoch == nil {
+			continue
+		}
+
+		if _, ok := (*epoch).ToInt(); ok {
+			continue
+		}
+
+		number, err := c.core.GetEpochNumber(*epoch)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get epoch number of %v", *epoch)
+		}
+
+		*epoch = types.NewEpochNumber(number)
+	}
+
+	return NewTraceIterator(ctx, c, traceFilter, option...)
+}
+
+// TraceIteratorOption is the option of TraceIterator
+type TraceIteratorOption struct {
+	// EpochSpan is the initial number of epochs to filter in a request, which is halved when the request
+	// failed due to node range limits, and doubled up to MaxEpochSpan when the results fit in a page until
+	// any range limit is hit.
+	EpochSpan    uint64
+	MaxEpochSpan uint64
+	// PageSize is the count of traces to filter in a request
+	PageSize uint64
+	// BufferSize is the size of traces channel
+	BufferSize int
+}
+
+var defaultTraceIteratorOption = TraceIteratorOption{
+	EpochSpan:    100,
+	MaxEpochSpan: 10000,
+	PageSize:     1000,
+	BufferSize:   100,
+}
+
+// TraceIterator streams traces matching a filter over a channel, e.g.
+//
+//	iter, _ := sdk.NewTraceIterator(ctx, client.Trace(), filter)
+//	for trace := range iter.Traces() {
+//		// handle trace
+//	}
+//
+//	if err := iter.Err(); err != nil {
+//		// handle error
+//	}
+//
+// The traces are retrieved in background only when the channel has space, and retrieving is stopped once
+// the context canceled or Close called.
+type TraceIterator struct {
+	trace  RpcTrace
+	filter types.TraceFilter
+	option TraceIteratorOption
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed atomic.Bool
+
+	traces chan types.LocalizedTrace
+	err    error
+}
+
+// NewTraceIterator creates an iterator that streams all traces matching filter. The epoch range of filter is
+// split into sub ranges adaptively, and each sub range is paged with After and Count, while the After and Count
+// of filter are applied to the whole stream. Note, the FromEpoch and ToEpoch of filter should be epoch numbers
+// if specified.
+func NewTraceIterator(ctx context.Context, trace RpcTrace, filter types.TraceFilter, option ...TraceIteratorOption) (*TraceIterator, error) {
+	opt := defaultTraceIteratorOption
+	if len(option) > 0 {
+		opt = option[0]
+	}
+
+	if opt.EpochSpan == 0 || opt.PageSize == 0 {
+		return nil, errors.New("epoch span and page size should be greater than 0")
+	}
+
+	if opt.MaxEpochSpan < opt.EpochSpan {
+		opt.MaxEpochSpan = opt.EpochSpan
+	}
+
+	if opt.BufferSize < 0 {
+		opt.BufferSize = 0
+	}
+
+	for _, epoch := range []*types.Epoch{filter.FromEpoch, filter.ToEpoch} {
+		if epoch == nil {
+			continue
+		}
+
+		if number, ok := epoch.ToInt(); !ok || !number.IsUint64() {
+			return nil, errors.Errorf("epoch %v is not an epoch number", epoch)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	iter := &TraceIterator{
+		trace:  trace,
+		filter: filter,
+		option: opt,
+		ctx:    ctx,
+		cancel: cancel,
+		traces: make(chan types.LocalizedTrace, opt.BufferSize),
+	}
+
+	go iter.run()
+
+	return iter, nil
+}
+
+// Traces returns the channel of traces, which is closed when all traces retrieved, any error occurred or
+// iterator closed.
+func (iter *TraceIterator) Traces() <-chan types.LocalizedTrace {
+	return iter.traces
+}
+
+// Err returns the error occurred during iteration if any, and should be called after the traces channel closed.
+func (iter *TraceIterator) Err() error {
+	return iter.err
+}
+
+// Close stops retrieving traces, and the traces channel will be closed.
+func (iter *TraceIterator) Close() {
+	iter.closed.Store(true)
+	iter.cancel()
+}
+
+func (iter *TraceIterator) run() {
+	defer close(iter.traces)
+	defer iter.cancel()
+
+	err := iter.iterate()
+	if err == nil || errors.Is(err, errTraceIteratorStopped) {
+		return
+	}
+
+	iter.err = err
+}
+
+var errTraceIteratorStopped = errors.New("trace iterator stopped")
+
+func (iter *TraceIterator) iterate() error {
+	var skip, limit uint64
+	if iter.filter.After != nil {
+		skip = uint64(*iter.filter.After)
+	}
+
+	if iter.filter.Count != nil {
+		limit = uint64(*iter.filter.Count)
+		if limit == 0 {
+			return nil
+		}
+	}
+
+	var sent uint64
+
+	emit := func(trace types.LocalizedTrace) error {
+		if skip > 0 {
+			skip--
+			return nil
+		}
+
+		select {
+		case iter.traces <- trace:
+		case <-iter.ctx.Done():
+			return iter.stopped()
+		}
+
+		sent++
+		if limit > 0 && sent >= limit {
+			return errTraceIteratorStopped
+		}
+
+		return nil
+	}
+
+	// filter by block hashes only
+	if iter.filter.FromEpoch == nil && iter.filter.ToEpoch == nil {
+		_, err := iter.filterRange(nil, nil, emit)
+		return err
+	}
+
+	from, to := uint64(0), uint64(0)
+	if iter.filter.FromEpoch != nil {
+		number, _ := iter.filter.FromEpoch.ToInt()
+		from = number.Uint64()
+	}
+
+	if iter.filter.ToEpoch != nil {
+		number, _ := iter.filter.ToEpoch.ToInt()
+		to = number.Uint64()
+	} else {
+		return errors.New("to epoch should be specified with from epoch")
+	}
+
+	span := iter.option.EpochSpan
+	// stop growing span once the range limit of node is hit, to avoid failing every other request
+	limited := false
+
+	for from <= to {
+		end := to
+		if span-1 < to-from {
+			end = from + span - 1
+		}
+
+		pages, err := iter.filterRange(types.NewEpochNumberUint64(from), types.NewEpochNumberUint64(end), emit)
+		if err != nil {
+			var rangeErr *traceRangeError
+			if !errors.As(err, &rangeErr) || span == 1 {
+				return err
+			}
+
+			// node range limits reached, retry with smaller range
+			span /= 2
+			limited = true
+			continue
+		}
+
+		if !limited && pages <= 1 && span < iter.option.MaxEpochSpan {
+			span *= 2
+			if span > iter.option.MaxEpochSpan {
+				span = iter.option.MaxEpochSpan
+			}
+		}
+
+		if end == to {
+			break
+		}
+
+		from = end + 1
+	}
+
+	return nil
+}
+
+// traceRangeError is the range limit error of first page request, which could be retried with smaller epoch
+// range since no trace of the range emitted yet.
+type traceRangeError struct {
+	err error
+}
+
+func (e *traceRangeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *traceRangeError) Unwrap() error {
+	return e.err
+}
+
+// filterRange pages traces in the epoch range, and returns the number of pages requested.
+func (iter *TraceIterator) filterRange(from, to *types.Epoch, emit func(types.LocalizedTrace) error) (int, error) {
+	filter := iter.filter
+	filter.FromEpoch = from
+	filter.ToEpoch = to
+
+	count := hexutil.Uint64(iter.option.PageSize)
+	filter.Count = &count
+
+	for page := 0; ; page++ {
+		if err := iter.ctx.Err(); err != nil {
+			return page, iter.stopped()
+		}
+
+		after := hexutil.Uint64(uint64(page) * iter.option.PageSize)
+		filter.After = &after
+
+		traces, err := iter.trace.FilterTraces(filter)
+		if err != nil {
+			err = errors.WithMessagef(err, "failed to filter traces of epoch [%v, %v] after %v", from, to, after)
+			if page == 0 && isTraceRangeLimitError(err) {
+				return page, &traceRangeError{err}
+			}
+
+			return page, err
+		}
+
+		for _, v := range traces {
+			if err := emit(v); err != nil {
+				return page + 1, err
+			}
+		}
+
+		if uint64(len(traces)) < iter.option.PageSize {
+			return page + 1, nil
+		}
+	}
+}
+
+// isTraceRangeLimitError returns true if the request is rejected by node due to the epoch range or the number
+// of traces exceeds limits, other than the transport errors.
+func isTraceRangeLimitError(err error) bool {
+	rpcErr, e := utils.ToRpcError(err)
+	if e != nil {
+		return false
+	}
+
+	msg := strings.ToLower(rpcErr.Message)
+	for _, v := range []string{"range", "gap", "too large", "too many", "exceed", "limit"} {
+		if strings.Contains(msg, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stopped returns the error of context, or errTraceIteratorStopped if closed by Close.
+func (iter *TraceIterator) stopped() error {
+	if iter.closed.Load() {
+		return errTraceIteratorStopped
+	}
+
+	return errors.WithStack(iter.ctx.Err())
+}
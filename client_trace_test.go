@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTrace struct {
+	RpcTrace
+	tracesPerEpoch int
+	maxEpochSpan   uint64 // range limit of node
+	requests       int
+	rangeErrors    int
+	transportErr   error
+}
+
+func (t *fakeTrace) FilterTraces(filter types.TraceFilter) ([]types.LocalizedTrace, error) {
+	t.requests++
+
+	if t.transportErr != nil {
+		return nil, t.transportErr
+	}
+
+	from, _ := filter.FromEpoch.ToInt()
+	to, _ := filter.ToEpoch.ToInt()
+
+	if to.Uint64()-from.Uint64()+1 > t.maxEpochSpan {
+		t.rangeErrors++
+		return nil, &utils.RpcError{Code: -32602, Message: "epoch range exceeds limit"}
+	}
+
+	var traces []types.LocalizedTrace
+	for epoch := from.Uint64(); epoch <= to.Uint64(); epoch++ {
+		for i := 0; i < t.tracesPerEpoch; i++ {
+			traces = append(traces, types.LocalizedTrace{EpochNumber: types.NewBigInt(epoch)})
+		}
+	}
+
+	after, count := uint64(*filter.After), uint64(*filter.Count)
+	if after >= uint64(len(traces)) {
+		return nil, nil
+	}
+
+	traces = traces[after:]
+	if uint64(len(traces)) > count {
+		traces = traces[:count]
+	}
+
+	return traces, nil
+}
+
+func collectTraceEpochs(iter *TraceIterator) (epochs []uint64) {
+	for v := range iter.Traces() {
+		epochs = append(epochs, v.EpochNumber.ToInt().Uint64())
+	}
+
+	return epochs
+}
+
+func newTraceFilter(from, to uint64) types.TraceFilter {
+	return types.TraceFilter{
+		FromEpoch: types.NewEpochNumberUint64(from),
+		ToEpoch:   types.NewEpochNumberUint64(to),
+	}
+}
+
+func TestTraceIterator(t *testing.T) {
+	trace := &fakeTrace{tracesPerEpoch: 3, maxEpochSpan: 4}
+	option := TraceIteratorOption{EpochSpan: 16, MaxEpochSpan: 64, PageSize: 5}
+
+	iter, err := NewTraceIterator(context.Background(), trace, newTraceFilter(10, 29), option)
+	assert.NoError(t, err)
+
+	epochs := collectTraceEpochs(iter)
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, 60, len(epochs))
+
+	for i, v := range epochs {
+		assert.Equal(t, uint64(10+i/3), v)
+	}
+
+	// span 16 and 8 rejected, then 4 epochs per request
+	assert.Equal(t, 2, trace.rangeErrors)
+	assert.Equal(t, 2+5*3, trace.requests)
+
+	// span grows until range limit hit, and never grows again
+	growing := &fakeTrace{tracesPerEpoch: 1, maxEpochSpan: 4}
+	iter, err = NewTraceIterator(context.Background(), growing, newTraceFilter(0, 31), TraceIteratorOption{EpochSpan: 4, MaxEpochSpan: 64, PageSize: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, 32, len(collectTraceEpochs(iter)))
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, 1, growing.rangeErrors)
+	assert.Equal(t, 1+1+7, growing.requests)
+
+	// skip and limit
+	filter := newTraceFilter(10, 29)
+	after, count := hexutil.Uint64(4), hexutil.Uint64(5)
+	filter.After, filter.Count = &after, &count
+
+	iter, err = NewTraceIterator(context.Background(), trace, filter, option)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{11, 11, 12, 12, 12}, collectTraceEpochs(iter))
+	assert.NoError(t, iter.Err())
+}
+
+func TestTraceIteratorError(t *testing.T) {
+	trace := &fakeTrace{tracesPerEpoch: 1, maxEpochSpan: 0}
+
+	iter, err := NewTraceIterator(context.Background(), trace, newTraceFilter(0, 10))
+	assert.NoError(t, err)
+	assert.Empty(t, collectTraceEpochs(iter))
+	assert.Error(t, iter.Err())
+
+	_, err = NewTraceIterator(context.Background(), trace, types.TraceFilter{FromEpoch: types.EpochLatestState})
+	assert.Error(t, err)
+
+	// transport error is returned without retrying with smaller range
+	trace = &fakeTrace{tracesPerEpoch: 1, maxEpochSpan: 100, transportErr: errors.New("connection refused")}
+
+	iter, err = NewTraceIterator(context.Background(), trace, newTraceFilter(0, 10))
+	assert.NoError(t, err)
+	assert.Empty(t, collectTraceEpochs(iter))
+	assert.ErrorContains(t, iter.Err(), "connection refused")
+	assert.Equal(t, 1, trace.requests)
+}
+
+func TestTraceIteratorCancel(t *testing.T) {
+	trace := &fakeTrace{tracesPerEpoch: 10, maxEpochSpan: 100}
+	option := TraceIteratorOption{EpochSpan: 10, PageSize: 10}
+
+	iter, err := NewTraceIterator(context.Background(), trace, newTraceFilter(0, 1000), option)
+	assert.NoError(t, err)
+
+	<-iter.Traces()
+	iter.Close()
+
+	for range iter.Traces() {
+	}
+	assert.NoError(t, iter.Err())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iter, err = NewTraceIterator(ctx, trace, newTraceFilter(0, 1000), option)
+	assert.NoError(t, err)
+
+	<-iter.Traces()
+	cancel()
+
+	for range iter.Traces() {
+	}
+	assert.ErrorIs(t, iter.Err(), context.Canceled)
+}
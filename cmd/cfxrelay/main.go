@@ -0,0 +1,209 @@
+// cfxrelay relays PoS blocks of Conflux network to the light node contract deployed on eSpace.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/internal/flags"
+	"github.com/Conflux-Chain/go-conflux-sdk/light"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3/web3go"
+	"github.com/openweb3/web3go/interfaces"
+	"github.com/openweb3/web3go/signers"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// envPrivateKey is the environment variable to specify the private key of relayer instead of config file
+const envPrivateKey = "CFXRELAY_PRIVATE_KEY"
+
+var configFlag = &cli.StringFlag{
+	Name:     "config",
+	Usage:    "Path to the JSON config file",
+	Required: true,
+}
+
+var app = flags.NewApp("Relay PoS blocks of Conflux network to light node contract on eSpace")
+
+// config is the JSON config of relayer, e.g.
+//
+//	{
+//	  "coreUrl": "https://main.confluxrpc.com",
+//	  "evmUrl": "https://evm.confluxrpc.com",
+//	  "lightNode": "0x...",
+//	  "ledgerInfo": "0x...",
+//	  "verifier": "0x...",
+//	  "epochFrom": 1000,
+//	  "gcLimits": 100,
+//	  "interval": "3s",
+//	  "maxBackoff": "1m",
+//	  "statusPath": "relayer.status.json",
+//	  "logLevel": "info"
+//	}
+type config struct {
+	CoreURL    string `json:"coreUrl"`
+	EvmURL     string `json:"evmUrl"`
+	PrivateKey string `json:"privateKey"` // relayer private key, could be specified by environment variable CFXRELAY_PRIVATE_KEY
+
+	LightNode  common.Address `json:"lightNode"`
+	LedgerInfo common.Address `json:"ledgerInfo"`
+	Verifier   common.Address `json:"verifier"`
+
+	EpochFrom uint64 `json:"epochFrom"`
+	GcLimits  int64  `json:"gcLimits"`
+	GasLimit  uint64 `json:"gasLimit"`
+
+	Interval   string `json:"interval"`
+	MaxBackoff string `json:"maxBackoff"`
+	StatusPath string `json:"statusPath"`
+	LogLevel   string `json:"logLevel"`
+}
+
+func init() {
+	app.Name = "cfxrelay"
+	app.Flags = []cli.Flag{configFlag}
+	app.Action = relay
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read config file %v", path)
+	}
+
+	var c config
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal config")
+	}
+
+	if privateKey := os.Getenv(envPrivateKey); privateKey != "" {
+		c.PrivateKey = privateKey
+	}
+
+	if c.CoreURL == "" || c.EvmURL == "" {
+		return nil, errors.New("Both coreUrl and evmUrl are required")
+	}
+
+	if c.PrivateKey == "" {
+		return nil, errors.Errorf("Private key not specified in config file or environment variable %v", envPrivateKey)
+	}
+
+	return &c, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+func (c *config) toRelayConfig(admin common.Address, logger logrus.FieldLogger) (light.EvmRelayConfig, error) {
+	interval, err := parseDuration(c.Interval)
+	if err != nil {
+		return light.EvmRelayConfig{}, errors.Wrap(err, "Invalid interval")
+	}
+
+	maxBackoff, err := parseDuration(c.MaxBackoff)
+	if err != nil {
+		return light.EvmRelayConfig{}, errors.Wrap(err, "Invalid maxBackoff")
+	}
+
+	return light.EvmRelayConfig{
+		LightNode:  c.LightNode,
+		LedgerInfo: c.LedgerInfo,
+		Verifier:   c.Verifier,
+		Admin:      admin,
+		EpochFrom:  c.EpochFrom,
+		GcLimits:   c.GcLimits,
+		GasLimit:   c.GasLimit,
+		Interval:   interval,
+		MaxBackoff: maxBackoff,
+		StatusPath: c.StatusPath,
+		Logger:     logger,
+	}, nil
+}
+
+func relay(ctx *cli.Context) error {
+	c, err := loadConfig(ctx.String(configFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	logger := logrus.New()
+	if c.LogLevel != "" {
+		level, err := logrus.ParseLevel(c.LogLevel)
+		if err != nil {
+			return errors.Wrap(err, "Invalid log level")
+		}
+		logger.SetLevel(level)
+	}
+
+	signer, err := signers.NewPrivateKeySignerByString(c.PrivateKey)
+	if err != nil {
+		return errors.WithMessage(err, "Invalid private key")
+	}
+
+	coreClient, err := sdk.NewClient(c.CoreURL)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to create core space client")
+	}
+	defer coreClient.Close()
+
+	relayerClient, err := web3go.NewClientWithOption(c.EvmURL, web3go.ClientOption{
+		SignerManager: signers.NewSignerManager([]interfaces.Signer{signer}),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "Failed to create eSpace client")
+	}
+	defer relayerClient.Close()
+
+	relayConfig, err := c.toRelayConfig(signer.Address(), logger)
+	if err != nil {
+		return err
+	}
+
+	relayer, err := light.NewEvmRelayer(coreClient, relayerClient, relayConfig)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to create relayer")
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err = relayer.Start(signalCtx); err != nil {
+		return errors.WithMessage(err, "Failed to start relayer")
+	}
+
+	logger.WithField("admin", signer.Address()).Info("Relayer started")
+
+	<-signalCtx.Done()
+	relayer.Stop()
+
+	status := relayer.Status()
+	logger.WithFields(logrus.Fields{
+		"epoch":     status.LastRelayedEpoch,
+		"round":     status.LastRelayedRound,
+		"pivot":     status.LastRelayedPivot,
+		"pendingGC": status.PendingGC,
+		"lastError": status.LastError,
+	}).Info("Relayer exited")
+
+	return nil
+}
@@ -16,9 +16,15 @@ const (
 	// MinGasprice represents the mininum gasprice required by conflux chain when sending transactions
 	// the value of main net is 1 Gdrip
 	MinGasprice = 1
+
+	// TransactionEpochBound is the maximum distance between the epoch height of transaction and the current epoch number
+	TransactionEpochBound = 100000
 )
 
 var (
+	// CollateralPerStorageByte is the storage collateral in Drip per byte, namely 1 CFX per 1024 bytes
+	CollateralPerStorageByte = big.NewInt(976562500000000)
+
 	MaxUint256, _ = new(big.Int).SetString("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 0)
 )
 
@@ -0,0 +1,314 @@
+package internalcontract
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/pkg/errors"
+)
+
+// ParamsTopic is the governance topic of ParamsControl contract
+type ParamsTopic uint16
+
+const (
+	ParamsTopicPowBaseReward ParamsTopic = iota
+	ParamsTopicInterestRate
+	ParamsTopicStoragePointProp
+	ParamsTopicBaseFeeShareProp
+)
+
+// ParamsTopics contains all governance topics ordered by topic index
+var ParamsTopics = []ParamsTopic{
+	ParamsTopicPowBaseReward,
+	ParamsTopicInterestRate,
+	ParamsTopicStoragePointProp,
+	ParamsTopicBaseFeeShareProp,
+}
+
+func (t ParamsTopic) String() string {
+	switch t {
+	case ParamsTopicPowBaseReward:
+		return "pow_base_reward"
+	case ParamsTopicInterestRate:
+		return "pos_interest_rate"
+	case ParamsTopicStoragePointProp:
+		return "storage_point_prop"
+	case ParamsTopicBaseFeeShareProp:
+		return "base_fee_share_prop"
+	}
+	return fmt.Sprintf("unknown_topic_%d", uint16(t))
+}
+
+// ParamsVoteOption is the vote option of a governance topic, which is the index of votes in ParamsControlVote
+type ParamsVoteOption int
+
+const (
+	ParamsVoteUnchanged ParamsVoteOption = iota
+	ParamsVoteIncrease
+	ParamsVoteDecrease
+)
+
+func (o ParamsVoteOption) String() string {
+	switch o {
+	case ParamsVoteUnchanged:
+		return "unchanged"
+	case ParamsVoteIncrease:
+		return "increase"
+	case ParamsVoteDecrease:
+		return "decrease"
+	}
+	return fmt.Sprintf("unknown_option_%d", int(o))
+}
+
+const (
+	// ParamsVoteFullPowerBlocks is the lock period for getting full vote power of the locked amount
+	ParamsVoteFullPowerBlocks = BlocksPerYear
+	// ParamsVoteHalfPowerBlocks is the lock period for getting half vote power of the locked amount
+	ParamsVoteHalfPowerBlocks = BlocksPerYear / 2
+	// ParamsVoteQuarterPowerBlocks is the lock period for getting a quarter vote power of the locked amount
+	ParamsVoteQuarterPowerBlocks = BlocksPerYear / 4
+)
+
+// ParamsVote is the typed votes of a governance topic
+type ParamsVote struct {
+	Topic     ParamsTopic
+	Unchanged *big.Int
+	Increase  *big.Int
+	Decrease  *big.Int
+}
+
+// ParamsTally is the vote result of a governance topic in a round
+type ParamsTally struct {
+	Round uint64
+	ParamsVote
+	// CurrentValue is the current value of the topic returned by cfx_getParamsFromVote
+	CurrentValue *big.Int
+}
+
+// ParamsVoter is used to cast votes to ParamsControl contract and tally the results
+type ParamsVoter struct {
+	client        sdk.ClientOperator
+	paramsControl ParamsControl
+}
+
+// NewParamsVote creates a ParamsVote which votes amount to the option of topic
+func NewParamsVote(topic ParamsTopic, option ParamsVoteOption, amount *big.Int) ParamsVote {
+	vote := ParamsVote{
+		Topic:     topic,
+		Unchanged: big.NewInt(0),
+		Increase:  big.NewInt(0),
+		Decrease:  big.NewInt(0),
+	}
+
+	switch option {
+	case ParamsVoteUnchanged:
+		vote.Unchanged = new(big.Int).Set(amount)
+	case ParamsVoteIncrease:
+		vote.Increase = new(big.Int).Set(amount)
+	case ParamsVoteDecrease:
+		vote.Decrease = new(big.Int).Set(amount)
+	}
+	return vote
+}
+
+func newParamsVoteFromRaw(raw ParamsControlVote) ParamsVote {
+	vote := ParamsVote{Topic: ParamsTopic(raw.TopicIndex)}
+	vote.Unchanged, vote.Increase, vote.Decrease = raw.Votes[ParamsVoteUnchanged], raw.Votes[ParamsVoteIncrease], raw.Votes[ParamsVoteDecrease]
+	return vote
+}
+
+// Total returns the sum of votes of all options
+func (v ParamsVote) Total() *big.Int {
+	total := big.NewInt(0)
+	for _, amount := range []*big.Int{v.Unchanged, v.Increase, v.Decrease} {
+		if amount != nil {
+			total.Add(total, amount)
+		}
+	}
+	return total
+}
+
+// Winner returns the option with most votes, ParamsVoteUnchanged is returned if there is a tie
+func (v ParamsVote) Winner() ParamsVoteOption {
+	winner, max := ParamsVoteUnchanged, v.Unchanged
+	for _, c := range []struct {
+		option ParamsVoteOption
+		amount *big.Int
+	}{{ParamsVoteIncrease, v.Increase}, {ParamsVoteDecrease, v.Decrease}} {
+		if c.amount != nil && (max == nil || c.amount.Cmp(max) > 0) {
+			winner, max = c.option, c.amount
+		}
+	}
+	return winner
+}
+
+func (v ParamsVote) toRaw() ParamsControlVote {
+	raw := ParamsControlVote{TopicIndex: uint16(v.Topic)}
+	for i, amount := range []*big.Int{v.Unchanged, v.Increase, v.Decrease} {
+		if amount == nil {
+			amount = big.NewInt(0)
+		}
+		raw.Votes[i] = amount
+	}
+	return raw
+}
+
+// NewParamsVoter creates a ParamsVoter
+func NewParamsVoter(client sdk.ClientOperator) (*ParamsVoter, error) {
+	paramsControl, err := NewParamsControl(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get params control contract")
+	}
+	return &ParamsVoter{client, paramsControl}, nil
+}
+
+// ParamsControl returns the underlying ParamsControl contract
+func (v *ParamsVoter) ParamsControl() *ParamsControl {
+	return &v.paramsControl
+}
+
+// GetVotePower returns the vote power of user computed by the vote lock list at the latest state
+func (v *ParamsVoter) GetVotePower(user types.Address) (*big.Int, error) {
+	status, err := v.client.GetStatus()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get status")
+	}
+
+	votes, err := v.client.GetVoteList(user, types.EpochLatestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vote list")
+	}
+
+	return ComputeParamsVotePower(votes, uint64(status.BlockNumber)), nil
+}
+
+// GetPosStakeForVotes returns the PoS stake counted as votes in the round
+func (v *ParamsVoter) GetPosStakeForVotes(round uint64) (*big.Int, error) {
+	stake, err := v.paramsControl.PosStakeForVotes(nil, round)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get pos stake for votes of round %v", round)
+	}
+	return stake, nil
+}
+
+// CastVote casts votes of voter in the current round, it returns error if votes of any topic exceed the vote power of voter
+func (v *ParamsVoter) CastVote(option *types.ContractMethodSendOption, voter types.Address, votes ...ParamsVote) (types.Hash, error) {
+	if len(votes) == 0 {
+		return "", errors.New("no vote to cast")
+	}
+
+	power, err := v.GetVotePower(voter)
+	if err != nil {
+		return "", err
+	}
+
+	raws := make([]ParamsControlVote, 0, len(votes))
+	topics := make(map[ParamsTopic]bool)
+	for _, vote := range votes {
+		if int(vote.Topic) >= len(ParamsTopics) {
+			return "", errors.Errorf("unknown topic %v", vote.Topic)
+		}
+		if topics[vote.Topic] {
+			return "", errors.Errorf("duplicated votes of topic %v", vote.Topic)
+		}
+		topics[vote.Topic] = true
+
+		if total := vote.Total(); total.Cmp(power) > 0 {
+			return "", errors.Errorf("votes %v of topic %v exceed vote power %v", total, vote.Topic, power)
+		}
+		raws = append(raws, vote.toRaw())
+	}
+
+	round, err := v.paramsControl.CurrentRound(nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get current round")
+	}
+
+	return v.paramsControl.CastVote(option, round, raws)
+}
+
+// ReadVote returns the typed votes of user in the current round
+func (v *ParamsVoter) ReadVote(user types.Address) ([]ParamsVote, error) {
+	raws, err := v.paramsControl.ReadVote(nil, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read vote")
+	}
+
+	votes := make([]ParamsVote, 0, len(raws))
+	for _, raw := range raws {
+		votes = append(votes, newParamsVoteFromRaw(raw))
+	}
+	return votes, nil
+}
+
+// Tally returns the vote results of all topics in the round
+func (v *ParamsVoter) Tally(round uint64) ([]ParamsTally, error) {
+	raws, err := v.paramsControl.TotalVotes(nil, round)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get total votes of round %v", round)
+	}
+
+	params, err := v.client.GetParamsFromVote()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get params from vote")
+	}
+
+	tallies := make([]ParamsTally, 0, len(raws))
+	for _, raw := range raws {
+		vote := newParamsVoteFromRaw(raw)
+		tallies = append(tallies, ParamsTally{
+			Round:        round,
+			ParamsVote:   vote,
+			CurrentValue: getParamsValue(params, vote.Topic),
+		})
+	}
+	return tallies, nil
+}
+
+// TallyRounds returns the vote results of rounds in range [from, to]
+func (v *ParamsVoter) TallyRounds(from, to uint64) (map[uint64][]ParamsTally, error) {
+	if from > to {
+		return nil, errors.Errorf("invalid round range [%v, %v]", from, to)
+	}
+
+	result := make(map[uint64][]ParamsTally)
+	for round := from; round <= to; round++ {
+		tallies, err := v.Tally(round)
+		if err != nil {
+			return nil, err
+		}
+		result[round] = tallies
+	}
+	return result, nil
+}
+
+// ComputeParamsVotePower computes the vote power at blockNumber by the vote lock list, the locked amount
+// provides full vote power if locked for one year, half for half a year and a quarter for a quarter year.
+func ComputeParamsVotePower(votes []types.VoteStakeInfo, blockNumber uint64) *big.Int {
+	// the locked amount is non-increasing as time goes, so the vote power could be accumulated by the amount locked at each threshold
+	quarter := ComputeLockedBalance(votes, blockNumber+ParamsVoteQuarterPowerBlocks-1)
+	half := ComputeLockedBalance(votes, blockNumber+ParamsVoteHalfPowerBlocks-1)
+	full := ComputeLockedBalance(votes, blockNumber+ParamsVoteFullPowerBlocks-1)
+
+	power := new(big.Int).Add(quarter, half)
+	power.Div(power, big.NewInt(4))
+	return power.Add(power, new(big.Int).Div(full, big.NewInt(2)))
+}
+
+func getParamsValue(params postypes.VoteParamsInfo, topic ParamsTopic) *big.Int {
+	var value *big.Int
+	switch topic {
+	case ParamsTopicPowBaseReward:
+		value = params.PowBaseReward.ToInt()
+	case ParamsTopicInterestRate:
+		value = params.InterestRate.ToInt()
+	case ParamsTopicStoragePointProp:
+		value = params.StoragePointProp.ToInt()
+	case ParamsTopicBaseFeeShareProp:
+		value = params.BaseFeeShareProp.ToInt()
+	}
+	return value
+}
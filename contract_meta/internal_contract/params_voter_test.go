@@ -0,0 +1,52 @@
+package internalcontract
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamsControlAbi(t *testing.T) {
+	_abi, err := abi.JSON(bytes.NewReader([]byte(getParamsControlAbi())))
+	assert.NoError(t, err)
+
+	_, err = _abi.Pack("currentRound")
+	assert.NoError(t, err)
+
+	_, err = _abi.Pack("posStakeForVotes", uint64(1))
+	assert.NoError(t, err)
+
+	vote := NewParamsVote(ParamsTopicInterestRate, ParamsVoteIncrease, big.NewInt(100))
+	_, err = _abi.Pack("castVote", uint64(1), []ParamsControlVote{vote.toRaw()})
+	assert.NoError(t, err)
+}
+
+func TestParamsVote(t *testing.T) {
+	vote := NewParamsVote(ParamsTopicBaseFeeShareProp, ParamsVoteDecrease, big.NewInt(100))
+	raw := vote.toRaw()
+	assert.Equal(t, uint16(3), raw.TopicIndex)
+	assert.Equal(t, "100", raw.Votes[2].String())
+	assert.Equal(t, ParamsVoteDecrease, vote.Winner())
+
+	vote = newParamsVoteFromRaw(ParamsControlVote{1, [3]*big.Int{big.NewInt(5), big.NewInt(5), big.NewInt(1)}})
+	assert.Equal(t, ParamsTopicInterestRate, vote.Topic)
+	assert.Equal(t, ParamsVoteUnchanged, vote.Winner())
+	assert.Equal(t, "11", vote.Total().String())
+}
+
+func TestComputeParamsVotePower(t *testing.T) {
+	votes := []types.VoteStakeInfo{
+		{Amount: (*hexutil.Big)(big.NewInt(400)), UnlockBlockNumber: 100 + ParamsVoteQuarterPowerBlocks},
+		{Amount: (*hexutil.Big)(big.NewInt(200)), UnlockBlockNumber: 100 + ParamsVoteHalfPowerBlocks},
+		{Amount: (*hexutil.Big)(big.NewInt(100)), UnlockBlockNumber: 100 + ParamsVoteFullPowerBlocks},
+	}
+
+	// 100 * 1 + 100 * 1/2 + 200 * 1/4
+	assert.Equal(t, "200", ComputeParamsVotePower(votes, 100).String())
+	assert.Equal(t, "0", ComputeParamsVotePower(votes, 101+ParamsVoteFullPowerBlocks).String())
+}
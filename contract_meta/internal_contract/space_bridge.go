@@ -0,0 +1,246 @@
+package internalcontract
+
+import (
+	"math/big"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/enums"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// ESpaceTransferFunc sends `amount` cfx to `to` in eSpace, it is used by SpaceBridge for the steps must be done in eSpace
+type ESpaceTransferFunc func(to common.Address, amount *big.Int) error
+
+// CrossSpaceTransfer is the cross space transfer result decoded from a transaction receipt
+type CrossSpaceTransfer struct {
+	TxHash types.Hash
+	// Success is true if both the transaction and all cross space calls are executed successfully
+	Success   bool
+	Calls     []*CrossSpaceCallCall
+	Creates   []*CrossSpaceCallCreate
+	Withdraws []*CrossSpaceCallWithdraw
+	Outcomes  []*CrossSpaceCallOutcome
+	// ToESpace is the total value transferred from core space to eSpace
+	ToESpace *big.Int
+	// FromESpace is the total value withdrawn from eSpace to core space
+	FromESpace *big.Int
+}
+
+// SpaceBalances is the balances of a core space account and its mapped account in eSpace
+type SpaceBalances struct {
+	Core          *big.Int
+	MappedAddress common.Address
+	Mapped        *big.Int
+}
+
+// SpaceBridge is used to transfer cfx between core space and eSpace by the CrossSpaceCall contract
+type SpaceBridge struct {
+	client         sdk.ClientOperator
+	crossSpaceCall CrossSpaceCall
+	// PollInterval is the interval to poll the receipt or mapped balance, default is 1 second
+	PollInterval time.Duration
+	// Timeout is the timeout to wait for the mapped balance be enough when withdrawing, default is 5 minutes
+	Timeout time.Duration
+}
+
+// NewSpaceBridge creates a SpaceBridge
+func NewSpaceBridge(client sdk.ClientOperator) (*SpaceBridge, error) {
+	crossSpaceCall, err := NewCrossSpaceCall(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cross space call contract")
+	}
+	return &SpaceBridge{
+		client:         client,
+		crossSpaceCall: crossSpaceCall,
+		PollInterval:   time.Second,
+		Timeout:        5 * time.Minute,
+	}, nil
+}
+
+// CrossSpaceCall returns the underlying CrossSpaceCall contract
+func (b *SpaceBridge) CrossSpaceCall() *CrossSpaceCall {
+	return &b.crossSpaceCall
+}
+
+// DepositToESpace transfers `amount` cfx from core space account `from` to eSpace address `evmTo`,
+// and returns the transfer result decoded from the receipt.
+func (b *SpaceBridge) DepositToESpace(option *types.ContractMethodSendOption, from types.Address, evmTo common.Address, amount *big.Int) (*CrossSpaceTransfer, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errors.New("deposit amount must be positive")
+	}
+
+	opt := types.ContractMethodSendOption{}
+	if option != nil {
+		opt = *option
+	}
+	opt.From = &from
+	opt.Value = types.NewBigIntByRaw(amount)
+
+	txHash, err := b.crossSpaceCall.TransferEVM(&opt, evmTo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send transferEVM transaction")
+	}
+
+	return b.waitTransfer(txHash)
+}
+
+// WithdrawFromESpace withdraws `amount` cfx from the mapped account of `from` in eSpace to `from` in core space.
+//
+// If the mapped balance is not enough, the lacking amount is transferred to the mapped address in eSpace by
+// `eSpaceTransfer`, then it waits for the mapped balance be enough. It returns error if the mapped balance
+// is not enough and `eSpaceTransfer` is nil.
+func (b *SpaceBridge) WithdrawFromESpace(option *types.ContractMethodSendOption, from types.Address, amount *big.Int, eSpaceTransfer ESpaceTransferFunc) (*CrossSpaceTransfer, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errors.New("withdraw amount must be positive")
+	}
+
+	balances, err := b.GetBalances(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if balances.Mapped.Cmp(amount) < 0 {
+		lacking := new(big.Int).Sub(amount, balances.Mapped)
+		if eSpaceTransfer == nil {
+			return nil, errors.Errorf("mapped balance %v is not enough, transfer %v to mapped address %v in eSpace first",
+				balances.Mapped, lacking, balances.MappedAddress)
+		}
+
+		if err = eSpaceTransfer(balances.MappedAddress, lacking); err != nil {
+			return nil, errors.WithMessagef(err, "failed to transfer %v to mapped address %v in eSpace", lacking, balances.MappedAddress)
+		}
+
+		if err = b.waitMappedBalance(from, amount); err != nil {
+			return nil, err
+		}
+	}
+
+	opt := types.ContractMethodSendOption{}
+	if option != nil {
+		opt = *option
+	}
+	opt.From = &from
+
+	txHash, err := b.crossSpaceCall.WithdrawFromMapped(&opt, amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send withdrawFromMapped transaction")
+	}
+
+	return b.waitTransfer(txHash)
+}
+
+// GetBalances returns the balance of `user` in core space and the balance of its mapped account in eSpace
+func (b *SpaceBridge) GetBalances(user types.Address) (*SpaceBalances, error) {
+	coreBalance, err := b.client.GetBalance(user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get core space balance")
+	}
+
+	mappedBalance, err := b.crossSpaceCall.MappedBalance(nil, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get mapped balance")
+	}
+
+	return &SpaceBalances{
+		Core:          coreBalance.ToInt(),
+		MappedAddress: user.GetMappedEVMSpaceAddress(),
+		Mapped:        mappedBalance,
+	}, nil
+}
+
+// GetTransfer returns the cross space transfer result of the transaction, it returns nil if the receipt is not found
+func (b *SpaceBridge) GetTransfer(txHash types.Hash) (*CrossSpaceTransfer, error) {
+	receipt, err := b.client.GetTransactionReceipt(txHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transaction receipt")
+	}
+	if receipt == nil {
+		return nil, nil
+	}
+	return b.DecodeTransfer(receipt)
+}
+
+// DecodeTransfer decodes the Call, Create, Withdraw and Outcome events of CrossSpaceCall contract from the receipt
+func (b *SpaceBridge) DecodeTransfer(receipt *types.TransactionReceipt) (*CrossSpaceTransfer, error) {
+	contractAddr, _, err := b.crossSpaceCall.Address.ToCommon()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cross space call address")
+	}
+
+	result := &CrossSpaceTransfer{
+		TxHash:     receipt.TransactionHash,
+		Success:    receipt.OutcomeStatus == hexutil.Uint64(enums.TRANSACTION_OUTCOME_SUCCESS),
+		ToESpace:   big.NewInt(0),
+		FromESpace: big.NewInt(0),
+	}
+
+	events := b.crossSpaceCall.ABI.Events
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 || log.Address.MustGetCommonAddress() != contractAddr {
+			continue
+		}
+
+		switch *log.Topics[0].ToCommonHash() {
+		case events["Call"].ID:
+			event, err := b.crossSpaceCall.ParseCall(log)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to decode Call event")
+			}
+			result.Calls = append(result.Calls, event)
+			result.ToESpace.Add(result.ToESpace, event.Value)
+		case events["Create"].ID:
+			event, err := b.crossSpaceCall.ParseCreate(log)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to decode Create event")
+			}
+			result.Creates = append(result.Creates, event)
+			result.ToESpace.Add(result.ToESpace, event.Value)
+		case events["Withdraw"].ID:
+			event, err := b.crossSpaceCall.ParseWithdraw(log)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to decode Withdraw event")
+			}
+			result.Withdraws = append(result.Withdraws, event)
+			result.FromESpace.Add(result.FromESpace, event.Value)
+		case events["Outcome"].ID:
+			event, err := b.crossSpaceCall.ParseOutcome(log)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to decode Outcome event")
+			}
+			result.Outcomes = append(result.Outcomes, event)
+			result.Success = result.Success && event.Success
+		}
+	}
+	return result, nil
+}
+
+func (b *SpaceBridge) waitTransfer(txHash types.Hash) (*CrossSpaceTransfer, error) {
+	receipt, err := b.client.WaitForTransationReceipt(txHash, b.PollInterval)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to wait for receipt of %v", txHash)
+	}
+	return b.DecodeTransfer(receipt)
+}
+
+func (b *SpaceBridge) waitMappedBalance(user types.Address, amount *big.Int) error {
+	deadline := time.Now().Add(b.Timeout)
+	for {
+		balance, err := b.crossSpaceCall.MappedBalance(nil, user)
+		if err != nil {
+			return errors.Wrap(err, "failed to get mapped balance")
+		}
+
+		if balance.Cmp(amount) >= 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout to wait for mapped balance reach %v, current %v", amount, balance)
+		}
+		time.Sleep(b.PollInterval)
+	}
+}
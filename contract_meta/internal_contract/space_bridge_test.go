@@ -0,0 +1,58 @@
+package internalcontract
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTransfer(t *testing.T) {
+	_abi, err := abi.JSON(strings.NewReader(getCrossSpaceCallAbi()))
+	assert.NoError(t, err)
+
+	address := cfxaddress.MustNewFromHex("0888000000000000000000000000000000000006", 1)
+	bridge := SpaceBridge{crossSpaceCall: CrossSpaceCall{sdk.Contract{ABI: _abi, Address: &address}}}
+
+	sender := common.HexToHash("0x1111111111111111111111111111111111111111000000000000000000000000")
+	receiver := common.HexToHash("0x2222222222222222222222222222222222222222000000000000000000000000")
+
+	callData, err := _abi.Events["Call"].Inputs.NonIndexed().Pack(big.NewInt(100), big.NewInt(1), []byte{})
+	assert.NoError(t, err)
+	outcomeData, err := _abi.Events["Outcome"].Inputs.NonIndexed().Pack(true)
+	assert.NoError(t, err)
+	otherAddress := cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000000", 1)
+
+	receipt := &types.TransactionReceipt{
+		TransactionHash: types.Hash("0x01"),
+		Logs: []types.Log{
+			{Address: address, Topics: []types.Hash{toHash(_abi.Events["Call"].ID), toHash(sender), toHash(receiver)}, Data: callData},
+			{Address: address, Topics: []types.Hash{toHash(_abi.Events["Outcome"].ID)}, Data: outcomeData},
+			{Address: otherAddress, Topics: []types.Hash{toHash(_abi.Events["Outcome"].ID)}, Data: outcomeData},
+		},
+	}
+
+	transfer, err := bridge.DecodeTransfer(receipt)
+	assert.NoError(t, err)
+	assert.True(t, transfer.Success)
+	assert.Equal(t, 1, len(transfer.Calls))
+	assert.Equal(t, 1, len(transfer.Outcomes))
+	assert.Equal(t, common.HexToAddress("0x2222222222222222222222222222222222222222"), transfer.Calls[0].Receiver)
+	assert.Equal(t, "100", transfer.ToESpace.String())
+	assert.Equal(t, "0", transfer.FromESpace.String())
+
+	receipt.OutcomeStatus = 1
+	transfer, err = bridge.DecodeTransfer(receipt)
+	assert.NoError(t, err)
+	assert.False(t, transfer.Success)
+}
+
+func toHash(h common.Hash) types.Hash {
+	return types.Hash(h.Hex())
+}
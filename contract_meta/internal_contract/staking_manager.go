@@ -0,0 +1,311 @@
+package internalcontract
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlocksPerSecond is the expected block generation rate of core space
+	BlocksPerSecond = 2
+	// BlocksPerYear is the number of blocks generated in one year, which is used by the protocol to compute interest
+	BlocksPerYear = BlocksPerSecond * 60 * 60 * 24 * 365
+)
+
+var (
+	// InterestRatePerBlockScale is the scale of interest rate per block in protocol, namely BlocksPerYear * 1e6.
+	// The annualized interest rate returned by cfx_getInterestRate is interest rate per block * BlocksPerYear,
+	// so it is scaled by InterestRatePerBlockScale too, e.g. 4% is represented as 0.04 * InterestRatePerBlockScale.
+	InterestRatePerBlockScale = new(big.Int).Mul(big.NewInt(BlocksPerYear), big.NewInt(1_000_000))
+)
+
+// StakingManager is used to manage the staking and vote lock of an account,
+// it wraps the Staking internal contract and the staking related RPCs.
+type StakingManager struct {
+	client  sdk.ClientOperator
+	staking Staking
+}
+
+// DepositDetail represents a deposit entry with the interest accrued until now
+type DepositDetail struct {
+	types.DepositInfo
+	AccruedInterest *big.Int
+}
+
+// UnlockEntry represents a vote lock entry that will be released at UnlockBlockNumber
+type UnlockEntry struct {
+	UnlockBlockNumber uint64
+	// Released is the amount become withdrawable when reaching UnlockBlockNumber
+	Released *big.Int
+	// LockedAfter is the amount still locked after UnlockBlockNumber
+	LockedAfter *big.Int
+	// EstimatedUnlockIn is the estimated duration to reach UnlockBlockNumber
+	EstimatedUnlockIn time.Duration
+}
+
+// StakingSummary represents the staking status of an account at BlockNumber
+type StakingSummary struct {
+	BlockNumber     uint64
+	StakingBalance  *big.Int
+	LockedBalance   *big.Int
+	Withdrawable    *big.Int
+	AccruedInterest *big.Int
+	Deposits        []DepositDetail
+	UnlockSchedule  []UnlockEntry
+}
+
+// NewStakingManager creates a StakingManager
+func NewStakingManager(client sdk.ClientOperator) (*StakingManager, error) {
+	staking, err := NewStaking(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get staking contract")
+	}
+	return &StakingManager{client, staking}, nil
+}
+
+// Staking returns the underlying Staking contract
+func (m *StakingManager) Staking() *Staking {
+	return &m.staking
+}
+
+// Deposit deposits `amount` cfx to the staking contract
+func (m *StakingManager) Deposit(option *types.ContractMethodSendOption, amount *big.Int) (types.Hash, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return "", errors.New("deposit amount must be positive")
+	}
+	return m.staking.Deposit(option, amount)
+}
+
+// Withdraw withdraws `amount` cfx from the staking contract, it returns error if the amount exceeds the withdrawable balance of user
+func (m *StakingManager) Withdraw(option *types.ContractMethodSendOption, user types.Address, amount *big.Int) (types.Hash, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return "", errors.New("withdraw amount must be positive")
+	}
+
+	withdrawable, err := m.GetWithdrawable(user)
+	if err != nil {
+		return "", err
+	}
+
+	if amount.Cmp(withdrawable) > 0 {
+		return "", errors.Errorf("withdraw amount %v exceeds withdrawable balance %v", amount, withdrawable)
+	}
+	return m.staking.Withdraw(option, amount)
+}
+
+// VoteLock locks `amount` cfx until `unlockBlockNumber` for obtaining vote power
+func (m *StakingManager) VoteLock(option *types.ContractMethodSendOption, amount *big.Int, unlockBlockNumber uint64) (types.Hash, error) {
+	if amount == nil || amount.Sign() < 0 {
+		return "", errors.New("vote lock amount must not be negative")
+	}
+
+	blockNumber, err := m.getBlockNumber()
+	if err != nil {
+		return "", err
+	}
+
+	if amount.Sign() > 0 && unlockBlockNumber <= blockNumber {
+		return "", errors.Errorf("unlock block number %v should be greater than current block number %v", unlockBlockNumber, blockNumber)
+	}
+	return m.staking.VoteLock(option, amount, new(big.Int).SetUint64(unlockBlockNumber))
+}
+
+// VoteLockFor locks `amount` cfx for `duration` from now, the unlock block number is estimated by BlocksPerSecond
+func (m *StakingManager) VoteLockFor(option *types.ContractMethodSendOption, amount *big.Int, duration time.Duration) (types.Hash, error) {
+	blockNumber, err := m.getBlockNumber()
+	if err != nil {
+		return "", err
+	}
+	unlockBlockNumber := blockNumber + uint64(duration/time.Second)*BlocksPerSecond
+	return m.VoteLock(option, amount, unlockBlockNumber)
+}
+
+// GetWithdrawable returns the withdrawable staking balance of user at the latest state
+func (m *StakingManager) GetWithdrawable(user types.Address) (*big.Int, error) {
+	summary, err := m.GetSummary(user)
+	if err != nil {
+		return nil, err
+	}
+	return summary.Withdrawable, nil
+}
+
+// GetSummary returns the staking balance, withdrawable amount, accrued interest and unlock schedule of user at the latest state
+func (m *StakingManager) GetSummary(user types.Address) (*StakingSummary, error) {
+	// pin the latest state epoch, so that all the states and the block number are read from the same epoch
+	epochNumber, err := m.client.GetEpochNumber(types.EpochLatestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest state epoch number")
+	}
+	epoch := types.NewEpochNumber(epochNumber)
+
+	pivotBlock, err := m.client.GetBlockSummaryByEpoch(epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pivot block of latest state epoch")
+	}
+	if pivotBlock == nil || pivotBlock.BlockNumber == nil {
+		return nil, errors.Errorf("block number of pivot block in epoch %v not available", epochNumber)
+	}
+
+	stakingBalance, err := m.client.GetStakingBalance(user, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get staking balance")
+	}
+
+	deposits, err := m.client.GetDepositList(user, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get deposit list")
+	}
+
+	votes, err := m.client.GetVoteList(user, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vote list")
+	}
+
+	accumulateInterestRate, err := m.client.GetAccumulateInterestRate(epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get accumulate interest rate")
+	}
+
+	blockNumber := pivotBlock.BlockNumber.ToInt().Uint64()
+	return ComputeStakingSummary(blockNumber, stakingBalance.ToInt(), deposits, votes, accumulateInterestRate.ToInt()), nil
+}
+
+// EstimateInterest estimates the interest of staking `amount` cfx for `blocks` blocks with the current interest rate.
+func (m *StakingManager) EstimateInterest(amount *big.Int, blocks uint64) (*big.Int, error) {
+	interestRate, err := m.client.GetInterestRate(types.EpochLatestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get interest rate")
+	}
+	return ComputeInterest(amount, interestRate.ToInt(), blocks), nil
+}
+
+func (m *StakingManager) getBlockNumber() (uint64, error) {
+	status, err := m.client.GetStatus()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get status")
+	}
+	return uint64(status.BlockNumber), nil
+}
+
+// ComputeStakingSummary computes the staking summary at blockNumber by the staking balance, deposit list, vote list and the accumulate interest rate at the same state
+func ComputeStakingSummary(blockNumber uint64, stakingBalance *big.Int, deposits []types.DepositInfo, votes []types.VoteStakeInfo, accumulateInterestRate *big.Int) *StakingSummary {
+	summary := &StakingSummary{
+		BlockNumber:     blockNumber,
+		StakingBalance:  new(big.Int).Set(stakingBalance),
+		LockedBalance:   ComputeLockedBalance(votes, blockNumber),
+		AccruedInterest: big.NewInt(0),
+		UnlockSchedule:  ComputeUnlockSchedule(votes, blockNumber),
+	}
+
+	summary.Withdrawable = new(big.Int).Sub(summary.StakingBalance, summary.LockedBalance)
+	if summary.Withdrawable.Sign() < 0 {
+		summary.Withdrawable = big.NewInt(0)
+	}
+
+	for _, d := range deposits {
+		interest := ComputeDepositInterest(d, accumulateInterestRate)
+		summary.AccruedInterest.Add(summary.AccruedInterest, interest)
+		summary.Deposits = append(summary.Deposits, DepositDetail{d, interest})
+	}
+	return summary
+}
+
+// ComputeLockedBalance returns the amount locked at blockNumber by vote list,
+// which is the max amount of the vote entries not unlocked yet.
+func ComputeLockedBalance(votes []types.VoteStakeInfo, blockNumber uint64) *big.Int {
+	locked := big.NewInt(0)
+	for _, v := range votes {
+		if v.UnlockBlockNumber <= blockNumber || v.Amount == nil {
+			continue
+		}
+		if v.Amount.ToInt().Cmp(locked) > 0 {
+			locked = new(big.Int).Set(v.Amount.ToInt())
+		}
+	}
+	return locked
+}
+
+// ComputeUnlockSchedule returns the unlock entries after blockNumber ordered by unlock block number
+func ComputeUnlockSchedule(votes []types.VoteStakeInfo, blockNumber uint64) []UnlockEntry {
+	var pendings []types.VoteStakeInfo
+	for _, v := range votes {
+		if v.UnlockBlockNumber > blockNumber && v.Amount != nil {
+			pendings = append(pendings, v)
+		}
+	}
+
+	sort.SliceStable(pendings, func(i, j int) bool {
+		return pendings[i].UnlockBlockNumber < pendings[j].UnlockBlockNumber
+	})
+
+	var schedule []UnlockEntry
+	locked := ComputeLockedBalance(pendings, blockNumber)
+	for _, v := range pendings {
+		lockedAfter := ComputeLockedBalance(pendings, v.UnlockBlockNumber)
+		released := new(big.Int).Sub(locked, lockedAfter)
+		if released.Sign() <= 0 {
+			continue
+		}
+
+		blocks := v.UnlockBlockNumber - blockNumber
+		schedule = append(schedule, UnlockEntry{
+			UnlockBlockNumber: v.UnlockBlockNumber,
+			Released:          released,
+			LockedAfter:       lockedAfter,
+			EstimatedUnlockIn: time.Duration(blocks/BlocksPerSecond) * time.Second,
+		})
+		locked = lockedAfter
+	}
+	return schedule
+}
+
+// ComputeDepositInterest returns the interest accrued by the deposit, which is calculated same as the protocol
+//
+//	interest = amount * accumulateInterestRate / deposit.AccumulatedInterestRate - amount
+func ComputeDepositInterest(deposit types.DepositInfo, accumulateInterestRate *big.Int) *big.Int {
+	if deposit.Amount == nil || deposit.AccumulatedInterestRate == nil || deposit.AccumulatedInterestRate.ToInt().Sign() == 0 {
+		return big.NewInt(0)
+	}
+
+	amount := deposit.Amount.ToInt()
+	interest := new(big.Int).Mul(amount, accumulateInterestRate)
+	interest.Div(interest, deposit.AccumulatedInterestRate.ToInt())
+	interest.Sub(interest, amount)
+	if interest.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return interest
+}
+
+// ComputeInterest returns the interest of `amount` staked for `blocks` blocks with the interest rate returned by cfx_getInterestRate,
+// the interest is compounded per block same as the accumulate interest rate of protocol.
+func ComputeInterest(amount *big.Int, interestRate *big.Int, blocks uint64) *big.Int {
+	if amount == nil || interestRate == nil || blocks == 0 {
+		return big.NewInt(0)
+	}
+
+	// (1 + ratePerBlock/scale)^blocks computed in big.Float to avoid iterating per block
+	prec := uint(256)
+	ratePerBlock := new(big.Int).Div(interestRate, big.NewInt(BlocksPerYear))
+	rate := new(big.Float).SetPrec(prec).SetInt(ratePerBlock)
+	rate.Quo(rate, new(big.Float).SetPrec(prec).SetInt(InterestRatePerBlockScale))
+	rate.Add(rate, big.NewFloat(1).SetPrec(prec))
+
+	factor := big.NewFloat(1).SetPrec(prec)
+	for base, n := rate, blocks; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			factor.Mul(factor, base)
+		}
+		base = new(big.Float).SetPrec(prec).Mul(base, base)
+	}
+
+	total := new(big.Float).SetPrec(prec).SetInt(amount)
+	total.Mul(total, factor)
+	result, _ := total.Int(nil)
+	return result.Sub(result, amount)
+}
@@ -0,0 +1,49 @@
+package internalcontract
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeStakingSummary(t *testing.T) {
+	votes := []types.VoteStakeInfo{
+		{Amount: (*hexutil.Big)(big.NewInt(100)), UnlockBlockNumber: 2000},
+		{Amount: (*hexutil.Big)(big.NewInt(300)), UnlockBlockNumber: 1200},
+		{Amount: (*hexutil.Big)(big.NewInt(500)), UnlockBlockNumber: 900},
+	}
+	deposits := []types.DepositInfo{
+		{Amount: (*hexutil.Big)(big.NewInt(1000)), AccumulatedInterestRate: (*hexutil.Big)(big.NewInt(100))},
+		{Amount: (*hexutil.Big)(big.NewInt(1000)), AccumulatedInterestRate: (*hexutil.Big)(big.NewInt(110))},
+	}
+
+	summary := ComputeStakingSummary(1000, big.NewInt(2000), deposits, votes, big.NewInt(121))
+
+	assert.Equal(t, "300", summary.LockedBalance.String())
+	assert.Equal(t, "1700", summary.Withdrawable.String())
+	assert.Equal(t, "210", summary.Deposits[0].AccruedInterest.String())
+	assert.Equal(t, "100", summary.Deposits[1].AccruedInterest.String())
+	assert.Equal(t, "310", summary.AccruedInterest.String())
+
+	assert.Equal(t, 2, len(summary.UnlockSchedule))
+	assert.Equal(t, uint64(1200), summary.UnlockSchedule[0].UnlockBlockNumber)
+	assert.Equal(t, "200", summary.UnlockSchedule[0].Released.String())
+	assert.Equal(t, "100", summary.UnlockSchedule[0].LockedAfter.String())
+	assert.Equal(t, 100*time.Second, summary.UnlockSchedule[0].EstimatedUnlockIn)
+	assert.Equal(t, uint64(2000), summary.UnlockSchedule[1].UnlockBlockNumber)
+	assert.Equal(t, "100", summary.UnlockSchedule[1].Released.String())
+	assert.Equal(t, "0", summary.UnlockSchedule[1].LockedAfter.String())
+}
+
+func TestComputeInterest(t *testing.T) {
+	assert.Equal(t, "0", ComputeInterest(big.NewInt(1e18), big.NewInt(40_000*BlocksPerYear), 0).String())
+
+	// 4% annual rate for one year should be a little more than 4% because of compounding
+	interest := ComputeInterest(big.NewInt(1e18), big.NewInt(40_000*BlocksPerYear), BlocksPerYear)
+	assert.True(t, interest.Cmp(big.NewInt(4e16)) > 0)
+	assert.True(t, interest.Cmp(big.NewInt(41e15)) < 0)
+}
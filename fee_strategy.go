@@ -0,0 +1,374 @@
+package sdk
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// FeeData is the gas fee suggested by FeeStrategy. The MaxFeePerGas and MaxPriorityFeePerGas are nil if
+// 1559 is not supported.
+type FeeData struct {
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// IsSupport1559 returns true if both MaxFeePerGas and MaxPriorityFeePerGas are suggested
+func (f *FeeData) IsSupport1559() bool {
+	return f.MaxFeePerGas != nil && f.MaxPriorityFeePerGas != nil
+}
+
+// FeeMarket is the fee market state at an epoch.
+type FeeMarket struct {
+	Epoch                uint64
+	GasPrice             *big.Int
+	BaseFeePerGas        *big.Int // nil if 1559 not supported
+	MaxPriorityFeePerGas *big.Int // nil if 1559 not supported
+}
+
+// FeeOracle provides the fee market data for FeeStrategy.
+type FeeOracle interface {
+	// Market returns the fee market state at latest state epoch.
+	Market() (*FeeMarket, error)
+	// FeeHistory returns the fee history of recent blockCount epochs with the reward percentiles.
+	FeeHistory(blockCount uint64, rewardPercentiles []float64) (*types.FeeHistory, error)
+}
+
+// FeeStrategy suggests gas fee of transactions.
+type FeeStrategy interface {
+	SuggestFee(oracle FeeOracle) (*FeeData, error)
+}
+
+type cachedFeeHistory struct {
+	epoch   uint64
+	key     string
+	history *types.FeeHistory
+}
+
+// epochFeeOracle caches the fee market data per epoch, so that the latest block is not fetched on every transaction.
+type epochFeeOracle struct {
+	client ClientOperator
+
+	mu      sync.Mutex
+	market  *FeeMarket
+	history *cachedFeeHistory
+}
+
+// NewFeeOracle creates a FeeOracle that caches the fee market data per latest state epoch.
+func NewFeeOracle(client ClientOperator) FeeOracle {
+	return &epochFeeOracle{client: client}
+}
+
+func (o *epochFeeOracle) Market() (*FeeMarket, error) {
+	epoch, err := o.client.GetEpochNumber(types.EpochLatestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest state epoch number")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.market != nil && o.market.Epoch == epoch.ToInt().Uint64() {
+		return o.market, nil
+	}
+
+	market := FeeMarket{Epoch: epoch.ToInt().Uint64()}
+
+	gasPrice, err := o.client.GetGasPrice()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get gas price")
+	}
+
+	// conflux responsed gasprice offen be 0, but the min gasprice is 1 when sending transaction
+	market.GasPrice = gasPrice.ToInt()
+	if market.GasPrice.Cmp(big.NewInt(constants.MinGasprice)) < 0 {
+		market.GasPrice = big.NewInt(constants.MinGasprice)
+	}
+
+	block, err := o.client.GetBlockSummaryByEpoch(types.NewEpochNumber(epoch))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block of epoch %v", epoch)
+	}
+
+	if block != nil && block.BaseFeePerGas != nil {
+		priorityFee, err := o.client.GetMaxPriorityFeePerGas()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get max priority fee per gas")
+		}
+
+		market.BaseFeePerGas = block.BaseFeePerGas.ToInt()
+		market.MaxPriorityFeePerGas = priorityFee.ToInt()
+	}
+
+	o.market = &market
+
+	return o.market, nil
+}
+
+func (o *epochFeeOracle) FeeHistory(blockCount uint64, rewardPercentiles []float64) (*types.FeeHistory, error) {
+	market, err := o.Market()
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%v-%v", blockCount, rewardPercentiles)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.history != nil && o.history.epoch == market.Epoch && o.history.key == key {
+		return o.history.history, nil
+	}
+
+	history, err := o.client.GetFeeHistory(types.HexOrDecimalUint64(blockCount), *types.NewEpochNumberUint64(market.Epoch), rewardPercentiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get fee history")
+	}
+
+	o.history = &cachedFeeHistory{market.Epoch, key, history}
+
+	return history, nil
+}
+
+// DefaultFeeStrategy suggests the priority fee by cfx_maxPriorityFeePerGas, and max fee per gas is
+// 2 * baseFee + priorityFee.
+type DefaultFeeStrategy struct{}
+
+func (DefaultFeeStrategy) SuggestFee(oracle FeeOracle) (*FeeData, error) {
+	market, err := oracle.Market()
+	if err != nil {
+		return nil, err
+	}
+
+	fee := FeeData{GasPrice: market.GasPrice}
+	if market.BaseFeePerGas == nil {
+		return &fee, nil
+	}
+
+	fee.MaxPriorityFeePerGas = market.MaxPriorityFeePerGas
+	fee.MaxFeePerGas = maxFeePerGas(market.BaseFeePerGas, 2, market.MaxPriorityFeePerGas)
+
+	return &fee, nil
+}
+
+// PercentileFeeStrategy suggests the priority fee by the median of the Percentile rewards of recent
+// BlockCount epochs, and max fee per gas is BaseFeeMultiplier * baseFee + priorityFee.
+type PercentileFeeStrategy struct {
+	Percentile        float64 // e.g. 50 for median
+	BlockCount        uint64  // 20 if not specified
+	BaseFeeMultiplier float64 // 2 if not specified
+}
+
+func (s PercentileFeeStrategy) SuggestFee(oracle FeeOracle) (*FeeData, error) {
+	market, err := oracle.Market()
+	if err != nil {
+		return nil, err
+	}
+
+	fee := FeeData{GasPrice: market.GasPrice}
+	if market.BaseFeePerGas == nil {
+		return &fee, nil
+	}
+
+	blockCount, multiplier := s.BlockCount, s.BaseFeeMultiplier
+	if blockCount == 0 {
+		blockCount = 20
+	}
+
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	history, err := oracle.FeeHistory(blockCount, []float64{s.Percentile})
+	if err != nil {
+		return nil, err
+	}
+
+	var rewards []*big.Int
+	for _, v := range history.Reward {
+		if len(v) > 0 && v[0] != nil {
+			rewards = append(rewards, v[0].ToInt())
+		}
+	}
+
+	if len(rewards) == 0 {
+		fee.MaxPriorityFeePerGas = market.MaxPriorityFeePerGas
+	} else {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		fee.MaxPriorityFeePerGas = rewards[len(rewards)/2]
+	}
+
+	fee.MaxFeePerGas = maxFeePerGas(market.BaseFeePerGas, multiplier, fee.MaxPriorityFeePerGas)
+
+	return &fee, nil
+}
+
+// FeeUrgency is the urgency level of transactions
+type FeeUrgency int
+
+const (
+	FeeUrgencySlow FeeUrgency = iota
+	FeeUrgencyNormal
+	FeeUrgencyFast
+)
+
+// NewUrgencyFeeStrategy creates a percentile based strategy for the urgency level, namely the 10th, 50th
+// and 90th percentile of priority fees with 1.25, 2 and 3 times of base fee for slow, normal and fast.
+func NewUrgencyFeeStrategy(urgency FeeUrgency) FeeStrategy {
+	switch urgency {
+	case FeeUrgencySlow:
+		return PercentileFeeStrategy{Percentile: 10, BaseFeeMultiplier: 1.25}
+	case FeeUrgencyFast:
+		return PercentileFeeStrategy{Percentile: 90, BaseFeeMultiplier: 3}
+	default:
+		return PercentileFeeStrategy{Percentile: 50, BaseFeeMultiplier: 2}
+	}
+}
+
+// FixedFeeStrategy always suggests the specified fee, and nil fields are suggested by DefaultFeeStrategy.
+type FixedFeeStrategy FeeData
+
+func (s FixedFeeStrategy) SuggestFee(oracle FeeOracle) (*FeeData, error) {
+	if s.GasPrice != nil && s.MaxFeePerGas != nil && s.MaxPriorityFeePerGas != nil {
+		fee := FeeData(s)
+		return &fee, nil
+	}
+
+	fee, err := DefaultFeeStrategy{}.SuggestFee(oracle)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.GasPrice != nil {
+		fee.GasPrice = s.GasPrice
+	}
+
+	if fee.IsSupport1559() {
+		if s.MaxFeePerGas != nil {
+			fee.MaxFeePerGas = s.MaxFeePerGas
+		}
+
+		if s.MaxPriorityFeePerGas != nil {
+			fee.MaxPriorityFeePerGas = s.MaxPriorityFeePerGas
+		}
+	}
+
+	return fee, nil
+}
+
+// CappedFeeStrategy caps the gas price and max fee per gas suggested by Strategy, or DefaultFeeStrategy if nil.
+type CappedFeeStrategy struct {
+	Strategy FeeStrategy
+	Cap      *big.Int
+}
+
+func (s CappedFeeStrategy) SuggestFee(oracle FeeOracle) (*FeeData, error) {
+	strategy := s.Strategy
+	if strategy == nil {
+		strategy = DefaultFeeStrategy{}
+	}
+
+	fee, err := strategy.SuggestFee(oracle)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Cap == nil {
+		return fee, nil
+	}
+
+	capped := FeeData{
+		GasPrice:             minBig(fee.GasPrice, s.Cap),
+		MaxFeePerGas:         minBig(fee.MaxFeePerGas, s.Cap),
+		MaxPriorityFeePerGas: minBig(fee.MaxPriorityFeePerGas, s.Cap),
+	}
+
+	return &capped, nil
+}
+
+func maxFeePerGas(baseFee *big.Int, multiplier float64, priorityFee *big.Int) *big.Int {
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(multiplier)).Int(nil)
+	return result.Add(result, priorityFee)
+}
+
+func minBig(value, cap *big.Int) *big.Int {
+	if value == nil || value.Cmp(cap) <= 0 {
+		return value
+	}
+
+	return cap
+}
+
+// ApplyFeeData sets the transaction type and gas fee fields of transaction that not specified by the fee data.
+func ApplyFeeData(tx *types.UnsignedTransaction, fee *FeeData) error {
+	if tx.GasPrice != nil && (tx.MaxFeePerGas != nil || tx.MaxPriorityFeePerGas != nil) {
+		return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+
+	if tx.GasPrice != nil && (tx.Type == nil || *tx.Type == types.TRANSACTION_TYPE_LEGACY) {
+		tx.Type = types.TRANSACTION_TYPE_LEGACY.Ptr()
+		return nil
+	}
+
+	has1559 := tx.MaxFeePerGas != nil || tx.MaxPriorityFeePerGas != nil
+
+	// set the txtype according to feeData
+	// - if support1559, then set txtype to 2
+	// - if not support1559
+	// - - if has maxFeePerGas or maxPriorityFeePerGas, then return error
+	// - - if contains accesslist, set txtype to 1
+	// - - else set txtype to 0
+	if tx.Type == nil {
+		if fee.IsSupport1559() {
+			tx.Type = types.TRANSACTION_TYPE_1559.Ptr()
+		} else {
+			if has1559 {
+				return errors.New("not support 1559 but (maxFeePerGas or maxPriorityFeePerGas) specified")
+			}
+
+			if tx.AccessList == nil {
+				tx.Type = types.TRANSACTION_TYPE_LEGACY.Ptr()
+			} else {
+				tx.Type = types.TRANSACTION_TYPE_2930.Ptr()
+			}
+		}
+	}
+
+	// if txtype is DynamicFeeTxType that means support 1559, so if gasPrice is not nil, set max... to gasPrice
+	if *tx.Type == types.TRANSACTION_TYPE_1559 {
+		if tx.GasPrice != nil {
+			tx.MaxFeePerGas = tx.GasPrice
+			tx.MaxPriorityFeePerGas = tx.GasPrice
+			tx.GasPrice = nil
+			return nil
+		}
+
+		if tx.MaxPriorityFeePerGas == nil {
+			tx.MaxPriorityFeePerGas = (*hexutil.Big)(fee.MaxPriorityFeePerGas)
+		}
+		if tx.MaxFeePerGas == nil {
+			tx.MaxFeePerGas = (*hexutil.Big)(fee.MaxFeePerGas)
+		}
+		if tx.MaxFeePerGas == nil || tx.MaxPriorityFeePerGas == nil {
+			return errors.New("maxFeePerGas and maxPriorityFeePerGas not available")
+		}
+		if tx.MaxFeePerGas.ToInt().Cmp(tx.MaxPriorityFeePerGas.ToInt()) < 0 {
+			return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", tx.MaxFeePerGas, tx.MaxPriorityFeePerGas)
+		}
+		return nil
+	}
+
+	if tx.GasPrice != nil {
+		return nil
+	}
+
+	tx.GasPrice = (*hexutil.Big)(fee.GasPrice)
+	return nil
+}
@@ -0,0 +1,140 @@
+package sdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFeeClient struct {
+	ClientOperator
+	epoch          uint64
+	baseFee        *big.Int
+	blockRequests  int
+	rewards        []int64
+	historyRequest int
+}
+
+func (c *fakeFeeClient) GetEpochNumber(epoch ...*types.Epoch) (*hexutil.Big, error) {
+	return types.NewBigInt(c.epoch), nil
+}
+
+func (c *fakeFeeClient) GetGasPrice() (*hexutil.Big, error) {
+	return types.NewBigInt(0), nil
+}
+
+func (c *fakeFeeClient) GetBlockSummaryByEpoch(epoch *types.Epoch) (*types.BlockSummary, error) {
+	c.blockRequests++
+
+	var block types.BlockSummary
+	block.BaseFeePerGas = (*hexutil.Big)(c.baseFee)
+
+	return &block, nil
+}
+
+func (c *fakeFeeClient) GetMaxPriorityFeePerGas() (*hexutil.Big, error) {
+	return types.NewBigInt(7), nil
+}
+
+func (c *fakeFeeClient) GetFeeHistory(blockCount types.HexOrDecimalUint64, lastEpoch types.Epoch, rewardPercentiles []float64) (*types.FeeHistory, error) {
+	c.historyRequest++
+
+	var history types.FeeHistory
+	for _, v := range c.rewards {
+		history.Reward = append(history.Reward, []*hexutil.Big{types.NewBigInt(uint64(v))})
+	}
+
+	return &history, nil
+}
+
+func TestFeeOracleCache(t *testing.T) {
+	client := &fakeFeeClient{epoch: 10, baseFee: big.NewInt(100), rewards: []int64{1, 2, 3}}
+	oracle := NewFeeOracle(client)
+
+	market, err := oracle.Market()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), market.GasPrice)
+	assert.Equal(t, big.NewInt(100), market.BaseFeePerGas)
+
+	_, err = oracle.Market()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.blockRequests)
+
+	_, err = oracle.FeeHistory(5, []float64{50})
+	assert.NoError(t, err)
+	_, err = oracle.FeeHistory(5, []float64{50})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.historyRequest)
+
+	client.epoch++
+	_, err = oracle.FeeHistory(5, []float64{50})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, client.blockRequests)
+	assert.Equal(t, 2, client.historyRequest)
+}
+
+func TestFeeStrategies(t *testing.T) {
+	client := &fakeFeeClient{epoch: 10, baseFee: big.NewInt(100), rewards: []int64{5, 1, 9, 3}}
+	oracle := NewFeeOracle(client)
+
+	fee, err := DefaultFeeStrategy{}.SuggestFee(oracle)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeData{big.NewInt(1), big.NewInt(207), big.NewInt(7)}, fee)
+
+	fee, err = NewUrgencyFeeStrategy(FeeUrgencyFast).SuggestFee(oracle)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), fee.MaxPriorityFeePerGas)
+	assert.Equal(t, big.NewInt(305), fee.MaxFeePerGas)
+
+	fee, err = NewUrgencyFeeStrategy(FeeUrgencySlow).SuggestFee(oracle)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(130), fee.MaxFeePerGas)
+
+	fee, err = FixedFeeStrategy{MaxPriorityFeePerGas: big.NewInt(2)}.SuggestFee(oracle)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeData{big.NewInt(1), big.NewInt(207), big.NewInt(2)}, fee)
+
+	fee, err = CappedFeeStrategy{Cap: big.NewInt(150)}.SuggestFee(oracle)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeData{big.NewInt(1), big.NewInt(150), big.NewInt(7)}, fee)
+
+	// 1559 not supported
+	client.baseFee = nil
+	client.epoch++
+
+	fee, err = NewUrgencyFeeStrategy(FeeUrgencyNormal).SuggestFee(oracle)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeData{GasPrice: big.NewInt(1)}, fee)
+	assert.False(t, fee.IsSupport1559())
+}
+
+func TestApplyFeeData(t *testing.T) {
+	fee := &FeeData{big.NewInt(1), big.NewInt(207), big.NewInt(7)}
+
+	var tx types.UnsignedTransaction
+	assert.NoError(t, ApplyFeeData(&tx, fee))
+	assert.Equal(t, types.TRANSACTION_TYPE_1559, *tx.Type)
+	assert.Equal(t, types.NewBigInt(207), tx.MaxFeePerGas)
+	assert.Equal(t, types.NewBigInt(7), tx.MaxPriorityFeePerGas)
+
+	tx = types.UnsignedTransaction{}
+	tx.GasPrice = types.NewBigInt(3)
+	assert.NoError(t, ApplyFeeData(&tx, fee))
+	assert.Equal(t, types.TRANSACTION_TYPE_LEGACY, *tx.Type)
+
+	tx = types.UnsignedTransaction{}
+	tx.MaxFeePerGas = types.NewBigInt(1)
+	assert.Error(t, ApplyFeeData(&tx, fee))
+
+	tx = types.UnsignedTransaction{}
+	tx.MaxFeePerGas = types.NewBigInt(1)
+	assert.Error(t, ApplyFeeData(&tx, &FeeData{GasPrice: big.NewInt(1)}))
+
+	tx = types.UnsignedTransaction{}
+	assert.NoError(t, ApplyFeeData(&tx, &FeeData{GasPrice: big.NewInt(1)}))
+	assert.Equal(t, types.TRANSACTION_TYPE_LEGACY, *tx.Type)
+	assert.Equal(t, types.NewBigInt(1), tx.GasPrice)
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/Conflux-Chain/go-conflux-sdk/types"
 	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
 	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	rpc "github.com/openweb3/go-rpc-provider"
 	"github.com/openweb3/go-rpc-provider/interfaces"
 )
@@ -219,5 +221,11 @@ type AccountManagerOperator interface {
 	Sign(tx types.UnsignedTransaction, passphrase string) (v byte, r, s []byte, err error)
 }
 
+// ESpaceSigner is interface of account manager which is able to sign eSpace transactions by its keys
+type ESpaceSigner interface {
+	ListESpace() []common.Address
+	SignESpaceTransaction(from common.Address, tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+}
+
 // reserve for forward compatbility
 type RpcRequester = interfaces.Provider
@@ -0,0 +1,85 @@
+// Package testutil provides the helpers shared by tests of packages in this module.
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	rpc "github.com/openweb3/go-rpc-provider"
+	"github.com/pkg/errors"
+)
+
+// FakeProvider responds rpc requests, including the batch requests, by Handler if set, otherwise with the
+// preset Errors or Results by method.
+type FakeProvider struct {
+	Results map[string]interface{}
+	Errors  map[string]error
+	Handler func(method string, args []interface{}) (interface{}, error)
+
+	mu sync.Mutex
+}
+
+// NewFakeProvider creates a FakeProvider with the preset results by method.
+func NewFakeProvider(results map[string]interface{}) *FakeProvider {
+	return &FakeProvider{
+		Results: results,
+		Errors:  make(map[string]error),
+	}
+}
+
+func (p *FakeProvider) respond(method string, args []interface{}) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Handler != nil {
+		return p.Handler(method, args)
+	}
+
+	if err, ok := p.Errors[method]; ok {
+		return nil, err
+	}
+
+	value, ok := p.Results[method]
+	if !ok {
+		return nil, errors.Errorf("unexpected method %v", method)
+	}
+
+	return value, nil
+}
+
+func (p *FakeProvider) call(result interface{}, method string, args []interface{}) error {
+	value, err := p.respond(method, args)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+func (p *FakeProvider) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return p.call(result, method, args)
+}
+
+func (p *FakeProvider) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for i := range b {
+		b[i].Error = p.call(b[i].Result, b[i].Method, b[i].Args)
+	}
+
+	return nil
+}
+
+func (p *FakeProvider) Subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *FakeProvider) SubscribeWithReconn(ctx context.Context, namespace string, channel interface{}, args ...interface{}) *rpc.ReconnClientSubscription {
+	return nil
+}
+
+func (p *FakeProvider) Close() {}
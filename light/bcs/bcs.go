@@ -1,6 +1,7 @@
 package bcs
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"reflect"
@@ -29,3 +30,30 @@ func MustEncodeToBytes(val interface{}) []byte {
 	}
 	return encoded
 }
+
+// Decode decodes BCS encoded data from r into val, which should be a non-nil pointer.
+//
+// Note, enum types are not supported by reflection, and should be decoded by ReadEnumIndex manually.
+func Decode(r io.Reader, val interface{}) error {
+	rval := reflect.ValueOf(val)
+	if rval.Kind() != reflect.Ptr || rval.IsNil() {
+		return errors.New("val should be a non-nil pointer")
+	}
+
+	return read(r, rval.Elem())
+}
+
+// DecodeBytes decodes BCS encoded data into val, and returns error if any trailing bytes remain.
+func DecodeBytes(data []byte, val interface{}) error {
+	r := bytes.NewReader(data)
+
+	if err := Decode(r, val); err != nil {
+		return err
+	}
+
+	if r.Len() > 0 {
+		return errors.New("trailing bytes after decoding")
+	}
+
+	return nil
+}
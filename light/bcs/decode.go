@@ -0,0 +1,345 @@
+package bcs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+func ReadUint8(r io.Reader) (uint8, error) {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return buf[0], nil
+}
+
+func ReadUint16(r io.Reader) (uint16, error) {
+	var buf [2]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint16(buf[:]), nil
+}
+
+func ReadUint32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func ReadUint64(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func ReadBool(r io.Reader) (bool, error) {
+	v, err := ReadUint8(r)
+	if err != nil {
+		return false, err
+	}
+
+	switch v {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, errors.Errorf("invalid bool value: %v", v)
+	}
+}
+
+func ReadInt8(r io.Reader) (int8, error) {
+	v, err := ReadUint8(r)
+	return int8(v), err
+}
+
+func ReadInt16(r io.Reader) (int16, error) {
+	v, err := ReadUint16(r)
+	return int16(v), err
+}
+
+func ReadInt32(r io.Reader) (int32, error) {
+	v, err := ReadUint32(r)
+	return int32(v), err
+}
+
+func ReadInt64(r io.Reader) (int64, error) {
+	v, err := ReadUint64(r)
+	return int64(v), err
+}
+
+// readULEB128 reads a canonical ULEB128 encoded uint32 value.
+func readULEB128(r io.Reader) (uint32, error) {
+	var value uint64
+
+	for shift := 0; shift < 32; shift += 7 {
+		b, err := ReadUint8(r)
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint64(b&0x7F) << shift
+
+		if b&0x80 == 0 {
+			if shift > 0 && b == 0 {
+				return 0, errors.New("non-canonical ULEB128 encoding")
+			}
+
+			if value > uint64(^uint32(0)) {
+				return 0, errors.New("ULEB128 value overflows uint32")
+			}
+
+			return uint32(value), nil
+		}
+	}
+
+	return 0, errors.New("ULEB128 value overflows uint32")
+}
+
+func ReadLen(r io.Reader) (int, error) {
+	len, err := readULEB128(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if len > MAX_SEQUENCE_LENGTH {
+		return 0, errors.Errorf("exceeded max sequence length: %v", len)
+	}
+
+	return int(len), nil
+}
+
+func ReadBytes(r io.Reader) ([]byte, error) {
+	len, err := ReadLen(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// read in chunks to avoid allocating huge memory for malformed length
+	var buf bytes.Buffer
+	if _, err = io.CopyN(&buf, r, int64(len)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	// empty sequence decoded as nil slice
+	return buf.Bytes(), nil
+}
+
+func ReadString(r io.Reader) (string, error) {
+	v, err := ReadBytes(r)
+	return string(v), err
+}
+
+func ReadOption(r io.Reader) (some bool, err error) {
+	return ReadBool(r)
+}
+
+func ReadEnumIndex(r io.Reader) (int, error) {
+	index, err := readULEB128(r)
+	return int(index), err
+}
+
+func read(r io.Reader, val reflect.Value) error {
+	switch val.Kind() {
+	case reflect.Bool:
+		v, err := ReadBool(r)
+		if err == nil {
+			val.SetBool(v)
+		}
+		return err
+	case reflect.Int8:
+		v, err := ReadInt8(r)
+		if err == nil {
+			val.SetInt(int64(v))
+		}
+		return err
+	case reflect.Int16:
+		v, err := ReadInt16(r)
+		if err == nil {
+			val.SetInt(int64(v))
+		}
+		return err
+	case reflect.Int32:
+		v, err := ReadInt32(r)
+		if err == nil {
+			val.SetInt(int64(v))
+		}
+		return err
+	case reflect.Int64:
+		v, err := ReadInt64(r)
+		if err == nil {
+			val.SetInt(v)
+		}
+		return err
+	case reflect.Uint8:
+		v, err := ReadUint8(r)
+		if err == nil {
+			val.SetUint(uint64(v))
+		}
+		return err
+	case reflect.Uint16:
+		v, err := ReadUint16(r)
+		if err == nil {
+			val.SetUint(uint64(v))
+		}
+		return err
+	case reflect.Uint32:
+		v, err := ReadUint32(r)
+		if err == nil {
+			val.SetUint(uint64(v))
+		}
+		return err
+	case reflect.Uint64:
+		v, err := ReadUint64(r)
+		if err == nil {
+			val.SetUint(v)
+		}
+		return err
+	case reflect.Array: // e.g. Hash, Address
+		return readArray(r, val)
+	case reflect.Interface:
+		return readInterface(r, val)
+	case reflect.Map:
+		return readMap(r, val)
+	case reflect.Ptr: // for rust Option type
+		return readOption(r, val)
+	case reflect.Slice:
+		return readSlice(r, val)
+	case reflect.String:
+		v, err := ReadString(r)
+		if err == nil {
+			val.SetString(v)
+		}
+		return err
+	case reflect.Struct:
+		return readStruct(r, val)
+	default:
+		return errors.Errorf("unsupported type kind: %v", val.Kind())
+	}
+}
+
+func readArray(r io.Reader, val reflect.Value) error {
+	if val.Type().Elem().Kind() == reflect.Uint8 {
+		buf := make([]byte, val.Len())
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+
+		reflect.Copy(val, reflect.ValueOf(buf))
+
+		return nil
+	}
+
+	for i, len := 0, val.Len(); i < len; i++ {
+		if err := read(r, val.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readInterface decodes value into the concrete type held by interface, since
+// there is no type information in BCS encoded data.
+func readInterface(r io.Reader, val reflect.Value) error {
+	if val.IsNil() {
+		return errors.Errorf("cannot decode into nil interface %v", val.Type())
+	}
+
+	elem := reflect.New(val.Elem().Type()).Elem()
+	if err := read(r, elem); err != nil {
+		return err
+	}
+
+	val.Set(elem)
+
+	return nil
+}
+
+func readOption(r io.Reader, val reflect.Value) error {
+	some, err := ReadOption(r)
+	if err != nil {
+		return err
+	}
+
+	if !some {
+		val.Set(reflect.Zero(val.Type()))
+		return nil
+	}
+
+	elem := reflect.New(val.Type().Elem())
+	if err = read(r, elem.Elem()); err != nil {
+		return err
+	}
+
+	val.Set(elem)
+
+	return nil
+}
+
+func readSlice(r io.Reader, val reflect.Value) error {
+	if val.Type().Elem().Kind() == reflect.Uint8 {
+		v, err := ReadBytes(r)
+		if err != nil {
+			return err
+		}
+
+		val.SetBytes(v)
+
+		return nil
+	}
+
+	len, err := ReadLen(r)
+	if err != nil {
+		return err
+	}
+
+	// empty sequence decoded as nil slice
+	slice := reflect.Zero(val.Type())
+	for i := 0; i < len; i++ {
+		elem := reflect.New(val.Type().Elem()).Elem()
+		if err = read(r, elem); err != nil {
+			return err
+		}
+
+		slice = reflect.Append(slice, elem)
+	}
+
+	val.Set(slice)
+
+	return nil
+}
+
+func readStruct(r io.Reader, val reflect.Value) error {
+	rtype := val.Type()
+
+	for i, len := 0, val.NumField(); i < len; i++ {
+		// ignore private field
+		if !rtype.Field(i).IsExported() {
+			continue
+		}
+
+		if err := read(r, val.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package bcs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertRoundTrip[T any](t *testing.T, val T) {
+	encoded := MustEncodeToBytes(val)
+
+	var decoded T
+	assert.NoError(t, DecodeBytes(encoded, &decoded))
+	assert.Equal(t, val, decoded)
+}
+
+func TestDecodePrimitives(t *testing.T) {
+	assertRoundTrip(t, true)
+	assertRoundTrip(t, false)
+	assertRoundTrip(t, int8(-1))
+	assertRoundTrip(t, uint8(1))
+	assertRoundTrip(t, int16(-4660))
+	assertRoundTrip(t, uint16(4660))
+	assertRoundTrip(t, int32(-305419896))
+	assertRoundTrip(t, uint32(305419896))
+	assertRoundTrip(t, int64(-1311768467750121216))
+	assertRoundTrip(t, uint64(1311768467750121216))
+
+	var val bool
+	assert.Error(t, DecodeBytes([]byte{2}, &val))
+}
+
+func TestDecodeOption(t *testing.T) {
+	assertRoundTrip(t, (*uint8)(nil))
+
+	val := uint8(8)
+	assertRoundTrip(t, &val)
+}
+
+func TestDecodeArray(t *testing.T) {
+	assertRoundTrip(t, [3]uint16{1, 2, 3})
+	assertRoundTrip(t, common.HexToHash("0x1234"))
+}
+
+func TestDecodeSlice(t *testing.T) {
+	assertRoundTrip(t, []uint16{1, 2})
+
+	val := make([]uint8, 9487)
+	val[9486] = 1
+	assertRoundTrip(t, val)
+
+	// length exceeds data
+	var decoded []byte
+	assert.Error(t, DecodeBytes([]byte{3, 1, 2}, &decoded))
+}
+
+func TestDecodeString(t *testing.T) {
+	assertRoundTrip(t, "çå∞≠¢õß∂ƒ∫")
+}
+
+func TestDecodeStruct(t *testing.T) {
+	type Foo struct {
+		Bool bool
+	}
+
+	type Bar struct {
+		Foo
+		Bytes  []byte
+		Label  string
+		Option *Foo
+		ignore int
+	}
+
+	type Zoo struct {
+		Inner Bar
+		Names []string
+	}
+
+	assertRoundTrip(t, Zoo{
+		Inner: Bar{
+			Foo:    Foo{true},
+			Bytes:  []byte{0xC0, 0xDE},
+			Label:  "a",
+			Option: &Foo{false},
+		},
+		Names: []string{"b", "c"},
+	})
+}
+
+func TestDecodeMap(t *testing.T) {
+	assertRoundTrip(t, map[byte]byte{'e': 'f', 'a': 'b', 'c': 'd'})
+	assertRoundTrip(t, map[common.Hash][]byte{
+		common.HexToHash("0x02"): {2},
+		common.HexToHash("0x01"): {1},
+	})
+
+	var val map[byte]byte
+
+	// keys not sorted
+	assert.Error(t, DecodeBytes([]byte{2, 'c', 'd', 'a', 'b'}, &val))
+
+	// duplicate keys
+	assert.Error(t, DecodeBytes([]byte{2, 'a', 'b', 'a', 'b'}, &val))
+}
+
+func TestDecodeULEB128(t *testing.T) {
+	for _, v := range []uint32{0, 1, 127, 128, 9487, 1 << 31, ^uint32(0)} {
+		var buf bytes.Buffer
+		_, err := writeULEB128(&buf, v)
+		assert.NoError(t, err)
+
+		decoded, err := readULEB128(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, v, decoded)
+	}
+
+	// non-canonical
+	_, err := readULEB128(bytes.NewReader([]byte{0x80, 0x00}))
+	assert.Error(t, err)
+
+	// overflow
+	_, err = readULEB128(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x10}))
+	assert.Error(t, err)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	var val uint16
+
+	// not a pointer
+	assert.Error(t, DecodeBytes([]byte{1, 0}, val))
+
+	// trailing bytes
+	assert.Error(t, DecodeBytes([]byte{1, 0, 0}, &val))
+
+	// unexpected EOF
+	assert.Error(t, DecodeBytes([]byte{1}, &val))
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"reflect"
 	"sort"
+
+	"github.com/pkg/errors"
 )
 
 type entry struct {
@@ -59,3 +61,59 @@ func writeMap(w io.Writer, val reflect.Value) (int, error) {
 
 	return count, nil
 }
+
+// recorder records the bytes read from underlying reader.
+type recorder struct {
+	r   io.Reader
+	buf bytes.Buffer
+}
+
+func (rec *recorder) Read(p []byte) (int, error) {
+	n, err := rec.r.Read(p)
+	rec.buf.Write(p[:n])
+	return n, err
+}
+
+func readMap(r io.Reader, val reflect.Value) error {
+	len, err := ReadLen(r)
+	if err != nil {
+		return err
+	}
+
+	rtype := val.Type()
+	if len == 0 {
+		val.Set(reflect.Zero(rtype))
+		return nil
+	}
+
+	m := reflect.MakeMapWithSize(rtype, len)
+
+	var lastKey []byte
+
+	for i := 0; i < len; i++ {
+		rec := recorder{r: r}
+
+		key := reflect.New(rtype.Key()).Elem()
+		if err = read(&rec, key); err != nil {
+			return err
+		}
+
+		// keys should be sorted by encoded bytes without duplication
+		if i > 0 && bytes.Compare(lastKey, rec.buf.Bytes()) >= 0 {
+			return errors.New("map keys are not in canonical order")
+		}
+
+		lastKey = rec.buf.Bytes()
+
+		value := reflect.New(rtype.Elem()).Elem()
+		if err = read(r, value); err != nil {
+			return err
+		}
+
+		m.SetMapIndex(key, value)
+	}
+
+	val.Set(m)
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package light
+
+import (
+	"math/big"
+
+	sdk "github.com/Conflux-Chain/go-conflux-sdk"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	evmTypes "github.com/openweb3/web3go/types"
+	"github.com/pkg/errors"
+)
+
+// VerifyBlockHash verifies the block hash against the block header fields.
+//
+// The evmBaseFeePerGas is the base fee of eSpace block in the same epoch, which is required if
+// the BaseFeePerGas of block header is not nil.
+func VerifyBlockHash(header *types.BlockHeader, evmBaseFeePerGas *big.Int) error {
+	hash, err := header.ComputeHash(evmBaseFeePerGas)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to compute block hash")
+	}
+
+	if hash != header.Hash {
+		return errors.Errorf("Block hash mismatch, expected = %v, computed = %v", header.Hash, hash)
+	}
+
+	return nil
+}
+
+// VerifyTransactionsRoot verifies the transactions root of block header against the transactions of both
+// core space and eSpace in the block, e.g. returned by Debug().GetTransactionsByBlock.
+func VerifyTransactionsRoot(header *types.BlockHeader, txs []types.WrapTransaction) error {
+	root := CreateTransactionsMPT(txs).Hash()
+
+	if expected := *header.TransactionsRoot.ToCommonHash(); root != expected {
+		return errors.Errorf("Transactions root mismatch, expected = %v, computed = %v", expected, root)
+	}
+
+	return nil
+}
+
+// VerifyBlockLinkage verifies the parent and referee linkage of blocks across a range of consecutive epochs.
+// Blocks of each epoch should be in execution order with pivot block at last, e.g. returned by GetBlocksByEpoch.
+//
+// Parent and referees out of the range are ignored.
+func VerifyBlockLinkage(epochs [][]*types.BlockHeader) error {
+	type position struct {
+		epoch  int
+		index  int
+		header *types.BlockHeader
+	}
+
+	positions := make(map[types.Hash]position)
+	for i, blocks := range epochs {
+		for j, block := range blocks {
+			positions[block.Hash] = position{i, j, block}
+		}
+	}
+
+	for i, blocks := range epochs {
+		if len(blocks) == 0 {
+			return errors.Errorf("No block in the %vth epoch", i)
+		}
+
+		pivot := blocks[len(blocks)-1]
+		if pivot.EpochNumber == nil || pivot.Height == nil || pivot.EpochNumber.ToInt().Cmp(pivot.Height.ToInt()) != 0 {
+			return errors.Errorf("Pivot block %v height mismatch with epoch number", pivot.Hash)
+		}
+
+		// pivot chain
+		if i > 0 {
+			if lastPivot := epochs[i-1][len(epochs[i-1])-1]; pivot.ParentHash != lastPivot.Hash {
+				return errors.Errorf("Parent of pivot block %v is not the previous pivot block %v", pivot.Hash, lastPivot.Hash)
+			}
+		}
+
+		for j, block := range blocks {
+			if block.EpochNumber == nil || block.EpochNumber.ToInt().Cmp(pivot.EpochNumber.ToInt()) != 0 {
+				return errors.Errorf("Block %v epoch number mismatch with pivot block %v", block.Hash, pivot.Hash)
+			}
+
+			// parent should be executed before block with the previous height
+			if parent, ok := positions[block.ParentHash]; ok {
+				if parent.epoch > i || (parent.epoch == i && parent.index >= j) {
+					return errors.Errorf("Parent %v is not executed before block %v", block.ParentHash, block.Hash)
+				}
+
+				if new(big.Int).Add(parent.header.Height.ToInt(), big.NewInt(1)).Cmp(block.Height.ToInt()) != 0 {
+					return errors.Errorf("Block %v height mismatch with parent %v", block.Hash, block.ParentHash)
+				}
+			}
+
+			// referees should be executed before block
+			for _, referee := range block.RefereeHashes {
+				if pos, ok := positions[referee]; ok && (pos.epoch > i || (pos.epoch == i && pos.index >= j)) {
+					return errors.Errorf("Referee %v is not executed before block %v", referee, block.Hash)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// BlockVerifier verifies the consistency of blocks fetched from full node.
+type BlockVerifier struct {
+	client sdk.ClientOperator
+}
+
+func NewBlockVerifier(client sdk.ClientOperator) *BlockVerifier {
+	return &BlockVerifier{client}
+}
+
+// VerifyBlock verifies the hash and transactions root of the specified block.
+//
+// For blocks with base fee, the base fee of eSpace block is required to compute block hash, which is only
+// available for pivot blocks by the eSpace client (see ClientOption.ESpaceNodeURL). Otherwise, the block
+// hash verification will be skipped.
+func (v *BlockVerifier) VerifyBlock(blockHash types.Hash) (*types.BlockSummary, error) {
+	block, err := v.client.GetBlockSummaryByHash(blockHash)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed to get block %v", blockHash)
+	}
+
+	if block == nil {
+		return nil, errors.Errorf("Block %v not found", blockHash)
+	}
+
+	if block.Hash != blockHash {
+		return nil, errors.Errorf("Block hash mismatch, expected = %v, actual = %v", blockHash, block.Hash)
+	}
+
+	evmBaseFeePerGas, ok, err := v.getEvmBaseFeePerGas(&block.BlockHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		if err = VerifyBlockHash(&block.BlockHeader, evmBaseFeePerGas); err != nil {
+			return nil, err
+		}
+	}
+
+	txs, err := v.client.Debug().GetTransactionsByBlock(blockHash)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed to get transactions of block %v", blockHash)
+	}
+
+	if err = VerifyTransactionsRoot(&block.BlockHeader, txs); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// VerifyEpochs verifies all blocks in epoch range [from, to] and the linkage among them.
+func (v *BlockVerifier) VerifyEpochs(from, to uint64) error {
+	if from > to {
+		return errors.Errorf("Invalid epoch range [%v, %v]", from, to)
+	}
+
+	var epochs [][]*types.BlockHeader
+
+	for epoch := from; epoch <= to; epoch++ {
+		hashes, err := v.client.GetBlocksByEpoch(types.NewEpochNumberUint64(epoch))
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to get blocks of epoch %v", epoch)
+		}
+
+		var headers []*types.BlockHeader
+		for _, hash := range hashes {
+			block, err := v.VerifyBlock(hash)
+			if err != nil {
+				return errors.WithMessagef(err, "Failed to verify block %v in epoch %v", hash, epoch)
+			}
+
+			headers = append(headers, &block.BlockHeader)
+		}
+
+		epochs = append(epochs, headers)
+	}
+
+	return VerifyBlockLinkage(epochs)
+}
+
+// espaceClient returns the eSpace client if the core space client is a *sdk.Client with eSpace configured.
+func (v *BlockVerifier) espaceClient() *sdk.RpcESpaceClient {
+	if client, ok := v.client.(*sdk.Client); ok {
+		return client.ESpace()
+	}
+
+	return nil
+}
+
+// getEvmBaseFeePerGas returns the base fee of eSpace block if required to compute block hash,
+// and returns false if not available.
+func (v *BlockVerifier) getEvmBaseFeePerGas(header *types.BlockHeader) (*big.Int, bool, error) {
+	if header.BaseFeePerGas == nil {
+		return nil, true, nil
+	}
+
+	// only pivot block has eSpace block with the same number as epoch
+	espace := v.espaceClient()
+	if espace == nil || header.EpochNumber == nil || header.EpochNumber.ToInt().Cmp(header.Height.ToInt()) != 0 {
+		return nil, false, nil
+	}
+
+	epoch := header.EpochNumber.ToInt().Uint64()
+	evmBlock, err := espace.Eth.BlockByNumber(evmTypes.NewBlockNumber(int64(epoch)), false)
+	if err != nil {
+		return nil, false, errors.WithMessagef(err, "Failed to get evm block by block number %v", epoch)
+	}
+
+	if evmBlock == nil || evmBlock.BaseFeePerGas == nil || evmBlock.Hash != *header.Hash.ToCommonHash() {
+		return nil, false, nil
+	}
+
+	return evmBlock.BaseFeePerGas, true, nil
+}
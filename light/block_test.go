@@ -0,0 +1,42 @@
+package light
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHeader(id, epoch, height int64, parent types.Hash, referees ...types.Hash) *types.BlockHeader {
+	return &types.BlockHeader{
+		Hash:          types.Hash(common.BigToHash(big.NewInt(id)).Hex()),
+		ParentHash:    parent,
+		EpochNumber:   (*hexutil.Big)(big.NewInt(epoch)),
+		Height:        (*hexutil.Big)(big.NewInt(height)),
+		RefereeHashes: referees,
+	}
+}
+
+func TestVerifyBlockLinkage(t *testing.T) {
+	p10 := newTestHeader(1, 10, 10, "")
+	b11 := newTestHeader(2, 11, 11, p10.Hash)
+	p11 := newTestHeader(3, 11, 11, p10.Hash)
+	p12 := newTestHeader(4, 12, 12, p11.Hash, b11.Hash)
+
+	assert.NoError(t, VerifyBlockLinkage([][]*types.BlockHeader{{p10}, {b11, p11}, {p12}}))
+
+	// pivot chain broken
+	assert.Error(t, VerifyBlockLinkage([][]*types.BlockHeader{{p10}, {p11, b11}, {p12}}))
+
+	// referee executed after block
+	b12 := newTestHeader(5, 12, 12, p11.Hash, p12.Hash)
+	assert.Error(t, VerifyBlockLinkage([][]*types.BlockHeader{{p10}, {b11, p11}, {b12, p12}}))
+
+	// height mismatch with parent
+	b13 := newTestHeader(6, 13, 14, p12.Hash)
+	p13 := newTestHeader(7, 13, 13, p12.Hash)
+	assert.Error(t, VerifyBlockLinkage([][]*types.BlockHeader{{p10}, {b11, p11}, {p12}, {b13, p13}}))
+}
@@ -0,0 +1,119 @@
+package mpt
+
+import (
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	// pathMaskFirstNibble indicates the first nibble of compressed path is missing
+	pathMaskFirstNibble = 0xf0
+	// pathMaskSecondNibble indicates the last nibble of compressed path is missing
+	pathMaskSecondNibble = 0x0f
+)
+
+// NewNibblePathFromCompressed creates NibblePath from the compressed path returned by RPC
+func NewNibblePathFromCompressed(pathSlice []byte, pathMask uint) NibblePath {
+	path := NewNibblePath(pathSlice)
+
+	if len(pathSlice) == 0 {
+		return path
+	}
+
+	if pathMask&pathMaskFirstNibble != 0 {
+		path.start++
+	}
+
+	if pathMask&pathMaskSecondNibble != 0 {
+		path.end--
+	}
+
+	return path
+}
+
+// ConvertTrieProof converts the trie proof returned by RPC into proof nodes ordered from root to leaf,
+// and returns the key and value of leaf node. The proof nodes could be verified by Prove.
+func ConvertTrieProof(root common.Hash, proof types.TrieProof) (nodes []*ProofNode, key []byte, value []byte, err error) {
+	if len(proof) == 0 {
+		return nil, nil, nil, errors.New("empty trie proof")
+	}
+
+	merkleToNode := make(map[common.Hash]*types.VanillaTrieNode)
+	for i := range proof {
+		merkleToNode[proof[i].MerkleHash] = &proof[i]
+	}
+
+	var nibbles []byte
+	expectedHash := root
+
+	for {
+		vanilla, ok := merkleToNode[expectedHash]
+		if !ok {
+			return nil, nil, nil, errors.Errorf("trie node %v not found in proof", expectedHash)
+		}
+		delete(merkleToNode, expectedHash)
+
+		node, err := newProofNodeFromVanilla(vanilla)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		nodes = append(nodes, node)
+		nibbles = append(nibbles, node.path.value()...)
+
+		// leaf node
+		if node.children[0] == KECCAKE_EMPTY {
+			value = node.value
+			break
+		}
+
+		// find the child in proof, there is only one child of branch node in proof for a single key
+		childIndex := -1
+		for i, child := range node.children {
+			if _, ok := merkleToNode[child]; ok && child != KECCAKE_EMPTY {
+				childIndex = i
+				break
+			}
+		}
+
+		if childIndex < 0 {
+			return nil, nil, nil, errors.Errorf("child of trie node %v not found in proof", vanilla.MerkleHash)
+		}
+
+		nibbles = append(nibbles, byte(childIndex))
+		expectedHash = node.children[childIndex]
+	}
+
+	// the first nibble of key is 0 if the root branch node with single child is trimmed
+	if len(nibbles)%2 == 1 {
+		nibbles = append([]byte{0}, nibbles...)
+	}
+
+	key = make([]byte, len(nibbles)/2)
+	for i := range key {
+		key[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+
+	return nodes, key, value, nil
+}
+
+func newProofNodeFromVanilla(vanilla *types.VanillaTrieNode) (*ProofNode, error) {
+	node := ProofNode{
+		path: NewNibblePathFromCompressed(vanilla.CompressedPath.PathSlice, uint(vanilla.CompressedPath.PathMask)),
+	}
+
+	if vanilla.MptValue != nil {
+		node.value = *vanilla.MptValue
+	}
+
+	switch len(vanilla.ChildrenTable) {
+	case 0:
+		node.children = LEAF_NODE_CHILDREN
+	case CHILDREN_COUNT:
+		copy(node.children[:], vanilla.ChildrenTable)
+	default:
+		return nil, errors.Errorf("invalid children table length %v of trie node %v", len(vanilla.ChildrenTable), vanilla.MerkleHash)
+	}
+
+	return &node, nil
+}
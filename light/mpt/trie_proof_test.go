@@ -0,0 +1,93 @@
+package mpt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// toTrieProof converts proof nodes into the RPC format in reverse order
+func toTrieProof(nodes []*ProofNode) types.TrieProof {
+	var proof types.TrieProof
+
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+
+		var vanilla types.VanillaTrieNode
+		if node.path.Length() > 0 {
+			vanilla.CompressedPath.PathSlice = node.path.fullBytes()
+			if node.path.start%2 == 1 {
+				vanilla.CompressedPath.PathMask |= pathMaskFirstNibble
+			}
+			if node.path.end%2 == 1 {
+				vanilla.CompressedPath.PathMask |= pathMaskSecondNibble
+			}
+		}
+
+		if node.children[0] != KECCAKE_EMPTY {
+			vanilla.ChildrenTable = append(vanilla.ChildrenTable, node.children[:]...)
+		}
+
+		if node.value != nil {
+			value := hexutil.Bytes(node.value)
+			vanilla.MptValue = &value
+		}
+
+		vanilla.MerkleHash = node.ComputeMerkle()
+		proof = append(proof, vanilla)
+	}
+
+	return proof
+}
+
+func TestConvertTrieProof(t *testing.T) {
+	valueFunc := func(i int) []byte {
+		return []byte(fmt.Sprintf("leaf node value - %v", i))
+	}
+
+	for leafNodes := 1; leafNodes <= 300; leafNodes += 7 {
+		keyLen := MinReprBytes(leafNodes)
+
+		var root Node
+		for i := 0; i < leafNodes; i++ {
+			root.Insert(ToIndexBytes(i, keyLen), valueFunc(i))
+		}
+
+		rootHash := root.Hash()
+
+		for i := 0; i < leafNodes; i++ {
+			proofNodes, ok := root.Proof(ToIndexBytes(i, keyLen))
+			assert.True(t, ok, "Failed to generate proof, leaf nodes = %v, index = %v", leafNodes, i)
+
+			nodes, key, value, err := ConvertTrieProof(rootHash, toTrieProof(proofNodes))
+			assert.NoError(t, err, "leaf nodes = %v, index = %v", leafNodes, i)
+			assert.Equal(t, ToIndexBytes(i, keyLen), key, "leaf nodes = %v, index = %v", leafNodes, i)
+			assert.Equal(t, valueFunc(i), value, "leaf nodes = %v, index = %v", leafNodes, i)
+			assert.True(t, Prove(rootHash, key, value, nodes), "Failed to prove, leaf nodes = %v, index = %v", leafNodes, i)
+		}
+	}
+}
+
+func TestConvertTrieProofInvalid(t *testing.T) {
+	var root Node
+	for i := 0; i < 20; i++ {
+		root.Insert(ToIndexBytes(i, 1), []byte{byte(i)})
+	}
+
+	proofNodes, _ := root.Proof(ToIndexBytes(3, 1))
+	proof := toTrieProof(proofNodes)
+
+	_, _, _, err := ConvertTrieProof(root.Hash(), nil)
+	assert.Error(t, err)
+
+	// root mismatch
+	_, _, _, err = ConvertTrieProof(KECCAKE_EMPTY, proof)
+	assert.Error(t, err)
+
+	// missing leaf node
+	_, _, _, err = ConvertTrieProof(root.Hash(), proof[1:])
+	assert.Error(t, err)
+}
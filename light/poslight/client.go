@@ -0,0 +1,237 @@
+// Package poslight implements a PoS light client, which follows the committee rotations of Conflux PoS chain
+// from a trusted checkpoint by verifying the aggregated BLS signatures of ledger infos. It is the off-chain
+// counterpart of the on-chain LightNode contract.
+package poslight
+
+import (
+	"sync"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// DefaultBatchEpochs is the default number of epochs to fetch ledger infos at a time
+const DefaultBatchEpochs = 100
+
+// LedgerProvider provides PoS ledger infos to verify, which is implemented by sdk.RpcPos
+type LedgerProvider interface {
+	GetStatus() (postypes.Status, error)
+	GetLedgerInfosByEpoch(startEpoch hexutil.Uint64, endEpoch hexutil.Uint64) ([]*postypes.LedgerInfoWithSignatures, error)
+	GetLedgerInfoByBlockNumber(blockNumber postypes.BlockNumber) (*postypes.LedgerInfoWithSignatures, error)
+}
+
+// Config is the configuration of PoS light client
+type Config struct {
+	// StatePath is the file to persist trusted state, the state is not persisted if empty
+	StatePath string
+	// BatchEpochs is the number of epochs to fetch ledger infos at a time, DefaultBatchEpochs is used if 0
+	BatchEpochs uint64
+}
+
+// Client is the PoS light client, which is safe for concurrent use.
+type Client struct {
+	provider LedgerProvider
+	config   Config
+
+	mu    sync.RWMutex
+	state *TrustedState
+}
+
+// NewClient creates a PoS light client with trusted state. If state is nil, the trusted state will be
+// loaded from Config.StatePath.
+func NewClient(provider LedgerProvider, state *TrustedState, config Config) (*Client, error) {
+	if config.BatchEpochs == 0 {
+		config.BatchEpochs = DefaultBatchEpochs
+	}
+
+	if state == nil {
+		if config.StatePath == "" {
+			return nil, errors.New("neither trusted state nor state path specified")
+		}
+
+		var err error
+		if state, err = LoadTrustedState(config.StatePath); err != nil {
+			return nil, errors.WithMessage(err, "failed to load trusted state")
+		}
+	} else if err := state.validate(); err != nil {
+		return nil, errors.WithMessage(err, "invalid trusted state")
+	}
+
+	return &Client{
+		provider: provider,
+		config:   config,
+		state:    state,
+	}, nil
+}
+
+// Epoch returns the current verified PoS epoch
+func (c *Client) Epoch() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.state.Epoch()
+}
+
+// LatestPivot returns the latest PoS finalized PoW pivot block, which may be nil if not available yet
+func (c *Client) LatestPivot() *postypes.PivotBlockDecision {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.state.Pivot == nil {
+		return nil
+	}
+
+	pivot := *c.state.Pivot
+	return &pivot
+}
+
+// State returns a copy of the trusted state, which should not be modified
+func (c *Client) State() TrustedState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return *c.state
+}
+
+// Sync fetches and verifies ledger infos from the current epoch to the latest committed PoS block,
+// and persists the trusted state if updated.
+func (c *Client) Sync() (updated bool, err error) {
+	status, err := c.provider.GetStatus()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to get pos status")
+	}
+
+	// follow the committee rotations by epoch ending ledger infos
+	for epoch := c.Epoch(); epoch < uint64(status.Epoch); epoch = c.Epoch() {
+		end := epoch + c.config.BatchEpochs
+		if end > uint64(status.Epoch) {
+			end = uint64(status.Epoch)
+		}
+
+		ledgers, err := c.provider.GetLedgerInfosByEpoch(hexutil.Uint64(epoch), hexutil.Uint64(end))
+		if err != nil {
+			return updated, errors.WithMessagef(err, "failed to get ledger infos of epoch [%v, %v)", epoch, end)
+		}
+
+		rotated, err := c.Update(ledgers...)
+		if rotated {
+			updated = true
+		}
+
+		if err != nil {
+			return updated, err
+		}
+
+		if c.Epoch() == epoch {
+			return updated, errors.Errorf("no epoch ending ledger info of epoch %v", epoch)
+		}
+	}
+
+	// verify the latest committed ledger info in current epoch
+	ledger, err := c.provider.GetLedgerInfoByBlockNumber(postypes.NewBlockNumber(uint64(status.LatestCommitted)))
+	if err != nil {
+		return updated, errors.WithMessage(err, "failed to get ledger info of the latest committed block")
+	}
+
+	if ledger != nil && uint64(ledger.LedgerInfo.CommitInfo.Epoch) == c.Epoch() {
+		latest, err := c.Update(ledger)
+		if latest {
+			updated = true
+		}
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	return updated, nil
+}
+
+// Update verifies the ledger infos in order and applies them to the trusted state, and persists the
+// trusted state if updated. Ledger infos of past epochs or rounds are ignored.
+func (c *Client) Update(ledgers ...*postypes.LedgerInfoWithSignatures) (updated bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// update on a copy so that the trusted state is not changed if failed to persist
+	state := *c.state
+
+	for _, ledger := range ledgers {
+		applied, err := apply(&state, ledger)
+		if err != nil {
+			return false, err
+		}
+
+		if applied {
+			updated = true
+		}
+	}
+
+	if !updated {
+		return false, nil
+	}
+
+	if c.config.StatePath != "" {
+		if err = state.Save(c.config.StatePath); err != nil {
+			return false, errors.WithMessage(err, "failed to persist trusted state")
+		}
+	}
+
+	c.state = &state
+
+	return true, nil
+}
+
+// apply verifies ledger info by committee of state, and updates state with the verified ledger info.
+func apply(state *TrustedState, ledger *postypes.LedgerInfoWithSignatures) (bool, error) {
+	if ledger == nil {
+		return false, nil
+	}
+
+	info := ledger.LedgerInfo.CommitInfo
+	epoch, round := uint64(info.Epoch), uint64(info.Round)
+
+	// ignore the ledger infos that already verified
+	if epoch < state.Epoch() || (epoch == state.Epoch() && round <= uint64(state.Round)) {
+		return false, nil
+	}
+
+	if epoch > state.Epoch() {
+		return false, errors.Errorf("committee of epoch %v not trusted yet, current epoch = %v", epoch, state.Epoch())
+	}
+
+	verified, err := ledger.Verify(state.Committee())
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to verify ledger info of epoch %v round %v", epoch, round)
+	}
+
+	if !verified {
+		return false, errors.Errorf("invalid signature of ledger info of epoch %v round %v", epoch, round)
+	}
+
+	if info.Pivot != nil {
+		if state.Pivot != nil && info.Pivot.Height < state.Pivot.Height {
+			return false, errors.Errorf("pivot block height decreased from %v to %v in epoch %v round %v",
+				uint64(state.Pivot.Height), uint64(info.Pivot.Height), epoch, round)
+		}
+
+		state.Pivot = info.Pivot
+	}
+
+	state.Round = info.Round
+
+	// rotate committee at epoch boundary
+	if committee, ok := ledger.NextCommittee(); ok {
+		if next := uint64(committee.State().Epoch); next != epoch+1 {
+			return false, errors.Errorf("invalid next epoch %v of ledger info in epoch %v", next, epoch)
+		}
+
+		state.rotate(committee, ledger.NextEpochValidators)
+		if err = state.validate(); err != nil {
+			return false, errors.WithMessagef(err, "invalid committee of epoch %v", epoch+1)
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,211 @@
+package poslight
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidator struct {
+	account    common.Hash
+	privateKey *big.Int
+}
+
+type testCommittee struct {
+	state      *postypes.EpochState
+	validators []testValidator
+}
+
+func newTestCommittee(epoch uint64, size int) testCommittee {
+	g1 := bls12381.NewG1()
+
+	committee := testCommittee{
+		state: &postypes.EpochState{
+			Epoch: hexutil.Uint64(epoch),
+			Verifier: postypes.ValidatorVerifier{
+				AddressToValidatorInfo: make(map[common.Hash]postypes.ValidatorConsensusInfo),
+				QuorumVotingPower:      hexutil.Uint64(size*2/3 + 1),
+				TotalVotingPower:       hexutil.Uint64(size),
+			},
+		},
+	}
+
+	for i := 0; i < size; i++ {
+		validator := testValidator{
+			account:    common.BigToHash(big.NewInt(int64(epoch*100 + uint64(i) + 1))),
+			privateKey: big.NewInt(int64(epoch*1000 + uint64(i) + 7)),
+		}
+
+		publicKey := g1.MulScalarBig(g1.New(), g1.One(), validator.privateKey)
+		committee.state.Verifier.AddressToValidatorInfo[validator.account] = postypes.ValidatorConsensusInfo{
+			PublicKey:   g1.ToCompressed(publicKey),
+			VotingPower: 1,
+		}
+		committee.validators = append(committee.validators, validator)
+	}
+
+	return committee
+}
+
+func (c testCommittee) publicKeys() map[common.Hash]hexutil.Bytes {
+	g1 := bls12381.NewG1()
+
+	keys := make(map[common.Hash]hexutil.Bytes)
+	for _, v := range c.validators {
+		keys[v.account] = g1.ToBytes(g1.MulScalarBig(g1.New(), g1.One(), v.privateKey))
+	}
+
+	return keys
+}
+
+// sign creates a ledger info of epoch and round signed by the first `signers` validators of committee
+func (c testCommittee) sign(t *testing.T, round, pivot uint64, next *testCommittee, signers int) *postypes.LedgerInfoWithSignatures {
+	ledger := &postypes.LedgerInfoWithSignatures{
+		LedgerInfo: postypes.LedgerInfo{
+			CommitInfo: postypes.BlockInfo{
+				Epoch:           c.state.Epoch,
+				Round:           hexutil.Uint64(round),
+				Id:              common.BigToHash(big.NewInt(int64(round))).Bytes(),
+				ExecutedStateId: common.Hash{}.Bytes(),
+				Pivot: &postypes.PivotBlockDecision{
+					Height:    hexutil.Uint64(pivot),
+					BlockHash: postypes.H256(common.BigToHash(big.NewInt(int64(pivot))).Hex()),
+				},
+			},
+			ConsensusDataHash: common.Hash{}.Bytes(),
+		},
+		Signatures: make(map[common.Hash]hexutil.Bytes),
+	}
+
+	if next != nil {
+		ledger.LedgerInfo.CommitInfo.NextEpochState = next.state
+		ledger.NextEpochValidators = next.publicKeys()
+	}
+
+	g2 := bls12381.NewG2()
+	aggregated := g2.Zero()
+	msg := ledger.EncodeBCS()
+
+	for _, v := range c.validators[:signers] {
+		signature, err := postypes.SignBLS(v.privateKey, msg)
+		assert.NoError(t, err)
+
+		point, err := g2.FromBytes(signature)
+		assert.NoError(t, err)
+
+		g2.Add(aggregated, aggregated, point)
+		ledger.Signatures[v.account] = signature
+	}
+
+	ledger.AggregatedSignature = g2.ToBytes(aggregated)
+
+	return ledger
+}
+
+type testProvider struct {
+	status       postypes.Status
+	epochEndings map[uint64]*postypes.LedgerInfoWithSignatures
+	latest       *postypes.LedgerInfoWithSignatures // ledger info of the latest committed block
+}
+
+func (p *testProvider) GetStatus() (postypes.Status, error) {
+	return p.status, nil
+}
+
+func (p *testProvider) GetLedgerInfosByEpoch(startEpoch hexutil.Uint64, endEpoch hexutil.Uint64) ([]*postypes.LedgerInfoWithSignatures, error) {
+	var ledgers []*postypes.LedgerInfoWithSignatures
+	for epoch := uint64(startEpoch); epoch < uint64(endEpoch); epoch++ {
+		if ledger, ok := p.epochEndings[epoch]; ok {
+			ledgers = append(ledgers, ledger)
+		}
+	}
+	return ledgers, nil
+}
+
+func (p *testProvider) GetLedgerInfoByBlockNumber(blockNumber postypes.BlockNumber) (*postypes.LedgerInfoWithSignatures, error) {
+	return p.latest, nil
+}
+
+func TestClientSync(t *testing.T) {
+	c1, c2, c3 := newTestCommittee(1, 4), newTestCommittee(2, 4), newTestCommittee(3, 4)
+
+	checkpoint := newTestCommittee(0, 4).sign(t, 9, 100, &c1, 3)
+	state, err := NewTrustedState(checkpoint)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), state.Epoch())
+
+	provider := &testProvider{
+		status: postypes.Status{Epoch: 3, LatestCommitted: 50},
+		epochEndings: map[uint64]*postypes.LedgerInfoWithSignatures{
+			1: c1.sign(t, 20, 200, &c2, 3),
+			2: c2.sign(t, 15, 300, &c3, 4),
+		},
+		latest: c3.sign(t, 5, 350, nil, 3),
+	}
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	client, err := NewClient(provider, state, Config{StatePath: statePath, BatchEpochs: 1})
+	assert.NoError(t, err)
+
+	updated, err := client.Sync()
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, uint64(3), client.Epoch())
+	assert.Equal(t, hexutil.Uint64(350), client.LatestPivot().Height)
+
+	// nothing changed
+	updated, err = client.Sync()
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// restore from disk
+	restored, err := NewClient(provider, nil, Config{StatePath: statePath})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), restored.Epoch())
+	assert.Equal(t, uint64(5), uint64(restored.State().Round))
+	assert.Equal(t, hexutil.Uint64(350), restored.LatestPivot().Height)
+}
+
+func TestClientUpdateInvalid(t *testing.T) {
+	c1, c2 := newTestCommittee(1, 4), newTestCommittee(2, 4)
+
+	state, err := NewTrustedState(newTestCommittee(0, 4).sign(t, 9, 100, &c1, 3))
+	assert.NoError(t, err)
+
+	client, err := NewClient(&testProvider{}, state, Config{})
+	assert.NoError(t, err)
+
+	// votes not enough
+	_, err = client.Update(c1.sign(t, 1, 110, nil, 2))
+	assert.Error(t, err)
+
+	// signed by untrusted committee
+	_, err = client.Update(c2.sign(t, 1, 110, nil, 3))
+	assert.Error(t, err)
+
+	// tampered ledger info
+	tampered := c1.sign(t, 1, 110, nil, 3)
+	tampered.LedgerInfo.CommitInfo.Pivot.Height = 120
+	_, err = client.Update(tampered)
+	assert.Error(t, err)
+
+	assert.Equal(t, uint64(1), client.Epoch())
+	assert.Equal(t, hexutil.Uint64(100), client.LatestPivot().Height)
+
+	// valid ledger info
+	updated, err := client.Update(c1.sign(t, 1, 110, nil, 3))
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, hexutil.Uint64(110), client.LatestPivot().Height)
+
+	// already verified
+	updated, err = client.Update(c1.sign(t, 1, 110, nil, 3))
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
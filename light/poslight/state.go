@@ -0,0 +1,115 @@
+package poslight
+
+import (
+	"encoding/json"
+	"os"
+
+	postypes "github.com/Conflux-Chain/go-conflux-sdk/types/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// TrustedState is the verified state of PoS light client, which could be persisted to disk and restored later.
+type TrustedState struct {
+	// EpochState is the committee of current epoch
+	EpochState *postypes.EpochState `json:"epochState"`
+	// Validators is the uncompressed BLS public keys of committee in 96 bytes
+	Validators map[common.Hash]hexutil.Bytes `json:"validators"`
+	// Round is the round of the latest verified ledger info in current epoch
+	Round hexutil.Uint64 `json:"round"`
+	// Pivot is the latest PoS finalized PoW pivot block
+	Pivot *postypes.PivotBlockDecision `json:"pivot"`
+}
+
+// NewTrustedState creates a TrustedState from a trusted checkpoint, which is the epoch ending ledger info
+// that contains the committee of next epoch, e.g. returned by GetLedgerInfoByEpoch(epoch - 1).
+func NewTrustedState(checkpoint *postypes.LedgerInfoWithSignatures) (*TrustedState, error) {
+	if checkpoint == nil {
+		return nil, errors.New("checkpoint is nil")
+	}
+
+	committee, ok := checkpoint.NextCommittee()
+	if !ok {
+		return nil, errors.New("next epoch state not found in checkpoint")
+	}
+
+	state := &TrustedState{
+		Pivot: checkpoint.LedgerInfo.CommitInfo.Pivot,
+	}
+	state.rotate(committee, checkpoint.NextEpochValidators)
+
+	if err := state.validate(); err != nil {
+		return nil, errors.WithMessage(err, "invalid checkpoint")
+	}
+
+	return state, nil
+}
+
+// LoadTrustedState loads TrustedState from the JSON file
+func LoadTrustedState(path string) (*TrustedState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %v", path)
+	}
+
+	var state TrustedState
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal trusted state")
+	}
+
+	if err = state.validate(); err != nil {
+		return nil, errors.WithMessagef(err, "invalid trusted state in file %v", path)
+	}
+
+	return &state, nil
+}
+
+// Save persists TrustedState to the JSON file, which is replaced atomically
+func (s *TrustedState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal trusted state")
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write file %v", tmp)
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		return errors.Wrapf(err, "failed to rename file %v to %v", tmp, path)
+	}
+
+	return nil
+}
+
+// Epoch returns the current PoS epoch
+func (s *TrustedState) Epoch() uint64 {
+	return uint64(s.EpochState.Epoch)
+}
+
+// Committee returns the committee of current epoch to verify ledger infos
+func (s *TrustedState) Committee() postypes.Committee {
+	return postypes.NewCommittee(s.EpochState, s.Validators)
+}
+
+func (s *TrustedState) rotate(committee postypes.Committee, validators map[common.Hash]hexutil.Bytes) {
+	s.EpochState = committee.State()
+	s.Validators = validators
+	s.Round = 0
+}
+
+func (s *TrustedState) validate() error {
+	if s.EpochState == nil {
+		return errors.New("epoch state is nil")
+	}
+
+	for account := range s.EpochState.Verifier.AddressToValidatorInfo {
+		if len(s.Validators[account]) == 0 {
+			return errors.Errorf("public key of validator %v not found", account)
+		}
+	}
+
+	return nil
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func MustRLPEncodeReceipt(receipt *types.TransactionReceipt) []byte {
-	storageCollateralized, storageReleased := constructStorageChanges(receipt)
+	storageCollateralized, storageReleased := ConstructStorageChanges(receipt)
 
 	val := []interface{}{
 		receipt.AccumulatedGasUsed.ToInt(),
@@ -42,7 +42,9 @@ type StorageChange struct {
 	Collaterals uint64
 }
 
-func constructStorageChanges(receipt *types.TransactionReceipt) (collateralized, released []StorageChange) {
+// ConstructStorageChanges returns the storage collateralized and released of receipt, and the collateralized
+// account is the called contract if storage covered by sponsor, otherwise the sender.
+func ConstructStorageChanges(receipt *types.TransactionReceipt) (collateralized, released []StorageChange) {
 	for _, v := range receipt.StorageReleased {
 		released = append(released, StorageChange{
 			Account:     v.Address.MustGetCommonAddress(),
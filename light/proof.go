@@ -17,7 +17,10 @@ import (
 
 const deferredExecutionEpochs uint64 = 5
 
-var ErrTransactionExecutionFailed = errors.New("transaction execution failed")
+var (
+	ErrTransactionExecutionFailed = errors.New("transaction execution failed")
+	ErrPivotNotRelayed            = errors.New("pivot block not relayed yet")
+)
 
 type ProofGenerator struct {
 	coreClient *sdk.Client
@@ -42,6 +45,8 @@ func NewProofGenerator(coreClient *sdk.Client, relayerClient *web3go.Client, lig
 // If receipt not found, it will return nil and requires client to retry later.
 //
 // If transaction execution failed, it will return `ErrTransactionExecutionFailed`.
+//
+// If the pivot block to verify receipt is not relayed yet, it will return `ErrPivotNotRelayed`.
 func (g *ProofGenerator) CreateReceiptProofEvm(evmClient *web3go.Client, txHash common.Hash) (*contract.TypesReceiptProof, error) {
 	receipt, err := evmClient.Eth.TransactionReceipt(txHash)
 	if err != nil {
@@ -61,15 +66,82 @@ func (g *ProofGenerator) CreateReceiptProofEvm(evmClient *web3go.Client, txHash
 		return nil, ErrTransactionExecutionFailed
 	}
 
-	pivot, err := g.lightNode.NearestPivot(nil, new(big.Int).SetUint64(receipt.BlockNumber+deferredExecutionEpochs))
+	pivot, err := g.nearestPivot(receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateReceiptProofEvm(g.coreClient, evmClient, txHash, receipt.BlockNumber, pivot)
+}
+
+// CreateReceiptProofCore returns the receipt proof for specified `txHash` on core space. The `evmClient` is
+// used to get the base fee of eSpace blocks for block headers, and `ESpace()` of core client is used if nil.
+//
+// If receipt not found, it will return nil and requires client to retry later.
+//
+// If transaction execution failed, it will return `ErrTransactionExecutionFailed`.
+//
+// If the pivot block to verify receipt is not relayed yet, it will return `ErrPivotNotRelayed`.
+func (g *ProofGenerator) CreateReceiptProofCore(evmClient *web3go.Client, txHash types.Hash) (*contract.TypesReceiptProof, error) {
+	receipt, err := g.coreClient.GetTransactionReceipt(txHash)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get transaction receipt")
+	}
+
+	if receipt == nil || receipt.EpochNumber == nil {
+		return nil, nil
+	}
+
+	// only return proof for success transaction
+	if receipt.MustGetOutcomeType() != enums.TRANSACTION_OUTCOME_SUCCESS {
+		return nil, ErrTransactionExecutionFailed
+	}
+
+	epochNumber := uint64(*receipt.EpochNumber)
+
+	pivot, err := g.nearestPivot(epochNumber)
 	if err != nil {
-		return nil, errors.WithMessage(err, "Failed to get nearest pivot on chain")
+		return nil, err
 	}
 
-	return CreateReceiptProofEvm(g.coreClient, evmClient, txHash, receipt.BlockNumber, pivot.Uint64())
+	return CreateReceiptProofCore(g.coreClient, evmClient, txHash, epochNumber, pivot)
+}
+
+// nearestPivot returns the nearest relayed pivot block on chain to verify receipts in the specified epoch.
+func (g *ProofGenerator) nearestPivot(epochNumber uint64) (uint64, error) {
+	state, err := g.lightNode.State(nil)
+	if err != nil {
+		return 0, errors.WithMessage(err, "Failed to get light node state")
+	}
+
+	height := epochNumber + deferredExecutionEpochs
+	if height > state.FinalizedBlockNumber.Uint64() {
+		return 0, ErrPivotNotRelayed
+	}
+
+	pivot, err := g.lightNode.NearestPivot(nil, new(big.Int).SetUint64(height))
+	if err != nil {
+		return 0, errors.WithMessage(err, "Failed to get nearest pivot on chain")
+	}
+
+	return pivot.Uint64(), nil
 }
 
 func CreateReceiptProofEvm(coreClient *sdk.Client, evmClient *web3go.Client, txHash common.Hash, epochNumber uint64, pivot uint64) (*contract.TypesReceiptProof, error) {
+	return createReceiptProof(coreClient, evmClient, txHash.Hex(), epochNumber, pivot)
+}
+
+// CreateReceiptProofCore returns the receipt proof for specified core space transaction executed in `epochNumber`,
+// which could be verified against the relayed `pivot` block on chain.
+func CreateReceiptProofCore(coreClient *sdk.Client, evmClient *web3go.Client, txHash types.Hash, epochNumber uint64, pivot uint64) (*contract.TypesReceiptProof, error) {
+	if evmClient == nil && coreClient.ESpace() != nil {
+		evmClient = coreClient.ESpace().Client
+	}
+
+	return createReceiptProof(coreClient, evmClient, txHash.String(), epochNumber, pivot)
+}
+
+func createReceiptProof(coreClient *sdk.Client, evmClient *web3go.Client, txHash string, epochNumber uint64, pivot uint64) (*contract.TypesReceiptProof, error) {
 	if epochNumber+deferredExecutionEpochs > pivot {
 		return nil, errors.New("invalid pivot")
 	}
@@ -77,12 +149,13 @@ func CreateReceiptProofEvm(coreClient *sdk.Client, evmClient *web3go.Client, txH
 	epoch := types.NewEpochNumberUint64(epochNumber)
 	epochOrHash := types.NewEpochOrBlockHashWithEpoch(epoch)
 
+	// receipts root contains receipts of both core space and eSpace
 	epochReceipts, err := coreClient.Debug().GetEpochReceipts(*epochOrHash, true)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "Failed to get receipts by epoch number %v", epochNumber)
 	}
 
-	blockIndex, receipt := matchReceipt(epochReceipts, txHash.Hex())
+	blockIndex, receipt := matchReceipt(epochReceipts, txHash)
 	if receipt == nil {
 		return nil, nil
 	}
@@ -106,8 +179,26 @@ func CreateReceiptProofEvm(coreClient *sdk.Client, evmClient *web3go.Client, txH
 		return nil, errors.New("Failed to generate receipt proof")
 	}
 
+	headers, err := createHeaders(coreClient, evmClient, epochNumber+deferredExecutionEpochs, pivot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contract.TypesReceiptProof{
+		Headers:      headers,
+		BlockIndex:   blockIndexKey,
+		BlockProof:   mpt.ConvertProofNode(blockProof),
+		ReceiptsRoot: receiptsRoot,
+		Index:        receiptKey,
+		Receipt:      primitives.MustRLPEncodeReceipt(receipt),
+		ReceiptProof: mpt.ConvertProofNode(receiptProof),
+	}, nil
+}
+
+// createHeaders returns the RLP encoded pivot block headers in epoch range [from, to].
+func createHeaders(coreClient *sdk.Client, evmClient *web3go.Client, from, to uint64) ([][]byte, error) {
 	var headers [][]byte
-	for i := epochNumber + deferredExecutionEpochs; i <= pivot; i++ {
+	for i := from; i <= to; i++ {
 		coreBlock, err := coreClient.GetBlockSummaryByEpoch(types.NewEpochNumberUint64(i))
 		if err != nil {
 			return nil, errors.WithMessagef(err, "Failed to get core block summary by epoch %v", i)
@@ -117,9 +208,13 @@ func CreateReceiptProofEvm(coreClient *sdk.Client, evmClient *web3go.Client, txH
 			return nil, errors.Errorf("Core block not found by epoch %v", i)
 		}
 
-		var evmBlock *evmTypes.Block
+		var evmBaseFeePerGas *big.Int
 		if coreBlock.BaseFeePerGas != nil {
-			evmBlock, err = evmClient.Eth.BlockByNumber(evmTypes.NewBlockNumber(int64(i)), false)
+			if evmClient == nil {
+				return nil, errors.Errorf("Evm client is required to get base fee of evm block %v", i)
+			}
+
+			evmBlock, err := evmClient.Eth.BlockByNumber(evmTypes.NewBlockNumber(int64(i)), false)
 			if err != nil {
 				return nil, errors.WithMessagef(err, "Failed to get evm block by block number %v", i)
 			}
@@ -131,20 +226,14 @@ func CreateReceiptProofEvm(coreClient *sdk.Client, evmClient *web3go.Client, txH
 			if evmBlock.BaseFeePerGas == nil {
 				return nil, errors.Errorf("There is no base fee in evm block by number %v", i)
 			}
+
+			evmBaseFeePerGas = evmBlock.BaseFeePerGas
 		}
 
-		headers = append(headers, primitives.MustRLPEncodeBlock(coreBlock, evmBlock.BaseFeePerGas))
+		headers = append(headers, primitives.MustRLPEncodeBlock(coreBlock, evmBaseFeePerGas))
 	}
 
-	return &contract.TypesReceiptProof{
-		Headers:      headers,
-		BlockIndex:   blockIndexKey,
-		BlockProof:   mpt.ConvertProofNode(blockProof),
-		ReceiptsRoot: receiptsRoot,
-		Index:        receiptKey,
-		Receipt:      primitives.MustRLPEncodeReceipt(receipt),
-		ReceiptProof: mpt.ConvertProofNode(receiptProof),
-	}, nil
+	return headers, nil
 }
 
 func matchReceipt(epochReceipts [][]types.TransactionReceipt, txHash string) (blockIndex int, receipt *types.TransactionReceipt) {
@@ -164,3 +253,97 @@ func matchReceipt(epochReceipts [][]types.TransactionReceipt, txHash string) (bl
 
 	return 0, nil
 }
+
+// TransactionProof is the inclusion proof of transaction in the transactions MPT of block.
+type TransactionProof struct {
+	BlockHash        types.Hash
+	TransactionsRoot common.Hash
+	Index            int    // index of transaction in block, including both core space and eSpace transactions
+	Key              []byte // key of transaction in the transactions MPT
+	Proof            []*mpt.ProofNode
+}
+
+// ContractProof converts the proof nodes for on-chain verification.
+func (p *TransactionProof) ContractProof() []contract.ProofLibProofNode {
+	return mpt.ConvertProofNode(p.Proof)
+}
+
+// CreateTransactionProof returns the inclusion proof for specified core space transaction `txHash`.
+//
+// If transaction not found or not packed, it will return nil and requires client to retry later.
+func (g *ProofGenerator) CreateTransactionProof(txHash types.Hash) (*TransactionProof, error) {
+	tx, err := g.coreClient.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get transaction")
+	}
+
+	if tx == nil || tx.BlockHash == nil {
+		return nil, nil
+	}
+
+	return CreateTransactionProof(g.coreClient, *tx.BlockHash, *txHash.ToCommonHash())
+}
+
+// CreateTransactionProof returns the inclusion proof for specified transaction `txHash` of either core space
+// or eSpace in block `blockHash`. Note, the transactions root of block is verified against all transactions
+// in block at first.
+//
+// If transaction not found in block, it will return nil.
+func CreateTransactionProof(client sdk.ClientOperator, blockHash types.Hash, txHash common.Hash) (*TransactionProof, error) {
+	block, err := client.GetBlockSummaryByHash(blockHash)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed to get block %v", blockHash)
+	}
+
+	if block == nil {
+		return nil, errors.Errorf("Block %v not found", blockHash)
+	}
+
+	txs, err := client.Debug().GetTransactionsByBlock(blockHash)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed to get transactions of block %v", blockHash)
+	}
+
+	return createTransactionProof(&block.BlockHeader, txs, txHash)
+}
+
+func createTransactionProof(header *types.BlockHeader, txs []types.WrapTransaction, txHash common.Hash) (*TransactionProof, error) {
+	root := CreateTransactionsMPT(txs)
+
+	if expected := *header.TransactionsRoot.ToCommonHash(); root.Hash() != expected {
+		return nil, errors.Errorf("Transactions root mismatch, expected = %v, computed = %v", expected, root.Hash())
+	}
+
+	index := matchTransaction(txs, txHash)
+	if index < 0 {
+		return nil, nil
+	}
+
+	key := mpt.IndexToKey(index, len(txs))
+	proof, ok := root.Proof(key)
+	if !ok {
+		return nil, errors.New("Failed to generate transaction proof")
+	}
+
+	return &TransactionProof{
+		BlockHash:        header.Hash,
+		TransactionsRoot: root.Hash(),
+		Index:            index,
+		Key:              key,
+		Proof:            proof,
+	}, nil
+}
+
+func matchTransaction(txs []types.WrapTransaction, txHash common.Hash) int {
+	for i, v := range txs {
+		if v.NativeTransaction != nil {
+			if *v.NativeTransaction.Hash.ToCommonHash() == txHash {
+				return i
+			}
+		} else if v.EthTransaction != nil && v.EthTransaction.Hash() == txHash {
+			return i
+		}
+	}
+
+	return -1
+}
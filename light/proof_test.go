@@ -0,0 +1,92 @@
+package light
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTransactions(n int) []types.WrapTransaction {
+	var txs []types.WrapTransaction
+
+	for i := 0; i < n; i++ {
+		if i%3 == 0 {
+			txs = append(txs, types.WrapTransaction{
+				EthTransaction: ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: uint64(i)}),
+			})
+		} else {
+			txs = append(txs, types.WrapTransaction{
+				NativeTransaction: &types.Transaction{Hash: types.Hash(common.BigToHash(big.NewInt(int64(i + 1))).Hex())},
+			})
+		}
+	}
+
+	return txs
+}
+
+func wrapTransactionHash(tx types.WrapTransaction) common.Hash {
+	if tx.NativeTransaction != nil {
+		return *tx.NativeTransaction.Hash.ToCommonHash()
+	}
+
+	return tx.EthTransaction.Hash()
+}
+
+func TestTransactionProof(t *testing.T) {
+	txs := newTestTransactions(20)
+	root := types.Hash(CreateTransactionsMPT(txs).Hash().Hex())
+	header := &types.BlockHeader{
+		Hash:             types.Hash(common.BigToHash(big.NewInt(100)).Hex()),
+		TransactionsRoot: root,
+	}
+
+	for i, tx := range txs {
+		txHash := wrapTransactionHash(tx)
+
+		proof, err := createTransactionProof(header, txs, txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, i, proof.Index)
+		assert.NotEmpty(t, proof.ContractProof())
+		assert.NoError(t, VerifyTransactionProof(header, txHash, proof))
+
+		// transaction mismatch
+		err = VerifyTransactionProof(header, wrapTransactionHash(txs[(i+1)%len(txs)]), proof)
+		assert.ErrorIs(t, err, ErrInvalidTransactionProof)
+	}
+
+	// transaction not found
+	proof, err := createTransactionProof(header, txs, common.HexToHash("0xdead"))
+	assert.NoError(t, err)
+	assert.Nil(t, proof)
+
+	// transactions root mismatch
+	_, err = createTransactionProof(header, txs[1:], wrapTransactionHash(txs[1]))
+	assert.Error(t, err)
+}
+
+func TestVerifyTransactionProofMismatch(t *testing.T) {
+	txs := newTestTransactions(5)
+	header := &types.BlockHeader{
+		Hash:             types.Hash(common.BigToHash(big.NewInt(100)).Hex()),
+		TransactionsRoot: types.Hash(CreateTransactionsMPT(txs).Hash().Hex()),
+	}
+
+	txHash := wrapTransactionHash(txs[2])
+	proof, err := createTransactionProof(header, txs, txHash)
+	assert.NoError(t, err)
+
+	// index mismatch
+	tampered := *proof
+	tampered.Index = 3
+	assert.ErrorIs(t, VerifyTransactionProof(header, txHash, &tampered), ErrInvalidTransactionProof)
+
+	// header mismatch
+	otherHeader := *header
+	otherHeader.TransactionsRoot = types.Hash(common.BigToHash(big.NewInt(1)).Hex())
+	otherHeader.Hash = proof.BlockHash
+	assert.ErrorIs(t, VerifyTransactionProof(&otherHeader, txHash, proof), ErrInvalidTransactionProof)
+}
@@ -1,7 +1,11 @@
 package light
 
 import (
+	"context"
+	"encoding/json"
 	"math/big"
+	"os"
+	"sync"
 	"time"
 
 	sdk "github.com/Conflux-Chain/go-conflux-sdk"
@@ -16,7 +20,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var RelayInterval = 3 * time.Second
+var (
+	// RelayInterval is the default interval to poll for new blocks to relay
+	RelayInterval = 3 * time.Second
+	// RelayMaxBackoff is the default maximum interval to retry on relay errors
+	RelayMaxBackoff = time.Minute
+)
+
+var ErrRelayerRunning = errors.New("relayer is already running")
 
 type EvmRelayConfig struct {
 	LightNode  common.Address // light node contract
@@ -28,6 +39,32 @@ type EvmRelayConfig struct {
 	GcLimits  int64  // maximum number of blocks to remove at a time
 
 	GasLimit uint64 // Fixed gas limit to send transaction if specified
+
+	Interval   time.Duration      // interval to poll for new blocks, RelayInterval by default
+	MaxBackoff time.Duration      // maximum interval to retry on errors, RelayMaxBackoff by default
+	StatusPath string             // file to persist relayer status if specified
+	Logger     logrus.FieldLogger // logger, logrus standard logger by default
+}
+
+// RelayerStatus is the status of EvmRelayer.
+type RelayerStatus struct {
+	Running     bool `json:"-"`
+	Initialized bool `json:"-"`
+
+	LastRelayedEpoch uint64    `json:"lastRelayedEpoch"`
+	LastRelayedRound uint64    `json:"lastRelayedRound"`
+	LastRelayedPivot uint64    `json:"lastRelayedPivot"`
+	LastRelayedAt    time.Time `json:"lastRelayedAt"`
+
+	// SkippedEpoch and SkippedRound is the last round skipped since no ledger info or pivot changed
+	SkippedEpoch uint64 `json:"skippedEpoch"`
+	SkippedRound uint64 `json:"skippedRound"`
+	// PendingGC is the number of PoW blocks that exceeds the maximum blocks in light node contract
+	PendingGC uint64 `json:"pendingGC"`
+
+	LastError         string    `json:"lastError,omitempty"`
+	LastErrorAt       time.Time `json:"lastErrorAt,omitempty"`
+	ConsecutiveErrors int       `json:"consecutiveErrors"`
 }
 
 type EvmRelayer struct {
@@ -36,17 +73,35 @@ type EvmRelayer struct {
 	relayerClient *web3go.Client
 	lightNode     *contract.LightNode
 	txOpts        *bind.TransactOpts
-	skippedRound  uint64
+	logger        logrus.FieldLogger
+
+	mu     sync.Mutex
+	status RelayerStatus
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-func NewEvmRelayer(coreClient *sdk.Client, relayerClient *web3go.Client, config EvmRelayConfig) *EvmRelayer {
+func NewEvmRelayer(coreClient *sdk.Client, relayerClient *web3go.Client, config EvmRelayConfig) (*EvmRelayer, error) {
 	backend, signer := relayerClient.ToClientForContract()
 	lightNode, err := contract.NewLightNode(config.LightNode, backend)
 	if err != nil {
-		panic(err.Error())
+		return nil, errors.WithMessage(err, "Failed to create light node contract")
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = RelayInterval
+	}
+
+	if config.MaxBackoff < config.Interval {
+		config.MaxBackoff = RelayMaxBackoff
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = logrus.StandardLogger()
 	}
 
-	return &EvmRelayer{
+	relayer := &EvmRelayer{
 		EvmRelayConfig: config,
 		coreClient:     coreClient,
 		relayerClient:  relayerClient,
@@ -56,45 +111,209 @@ func NewEvmRelayer(coreClient *sdk.Client, relayerClient *web3go.Client, config
 			Signer:   signer,
 			GasLimit: config.GasLimit,
 		},
+		logger: logger,
+	}
+
+	if config.StatusPath != "" {
+		if err = relayer.loadStatus(); err != nil {
+			return nil, err
+		}
+	}
+
+	return relayer, nil
+}
+
+// Start starts to relay blocks in a separate goroutine until ctx is done or Stop is called.
+func (r *EvmRelayer) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status.Running {
+		return ErrRelayerRunning
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	r.status.Running = true
+
+	go r.run(ctx, r.done)
+
+	return nil
+}
+
+// Stop stops relaying blocks and waits for the ongoing relay to complete.
+func (r *EvmRelayer) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	// wait for completion even if stopped due to context done
+	if done != nil {
+		<-done
 	}
 }
 
+// Status returns the current status of relayer.
+func (r *EvmRelayer) Status() RelayerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.status
+}
+
+// Relay relays blocks and blocks until Stop is called.
+//
+// Deprecated: use Start and Stop instead.
 func (r *EvmRelayer) Relay() {
-	var initialized bool
+	if err := r.Start(context.Background()); err != nil {
+		r.logger.WithError(err).Warn("Failed to start relayer")
+		return
+	}
+
+	r.mu.Lock()
+	done := r.done
+	r.mu.Unlock()
+
+	<-done
+}
+
+func (r *EvmRelayer) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	defer func() {
+		r.mu.Lock()
+		r.status.Running = false
+		r.cancel = nil
+		r.mu.Unlock()
+	}()
+
+	backoff := r.Interval
 
 	for {
-		relayed, err := r.relay(initialized)
+		relayed, err := r.relay(ctx)
+
+		var wait time.Duration
 		if err != nil {
-			logrus.WithError(err).Warn("Failed to relay")
-		} else if relayed {
-			if !initialized {
-				initialized = true
+			r.onError(err)
+			r.logger.WithError(err).WithField("retryIn", backoff).Warn("Failed to relay")
+
+			wait = backoff
+			backoff = nextBackoff(backoff, r.MaxBackoff)
+		} else {
+			r.onSuccess()
+			backoff = r.Interval
+
+			if !relayed {
+				wait = r.Interval
 			}
+		}
 
-			continue
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Relayer stopped")
+			return
+		case <-time.After(wait):
 		}
+	}
+}
+
+// nextBackoff doubles the current backoff interval without exceeding max.
+func nextBackoff(current, max time.Duration) time.Duration {
+	if next := 2 * current; next < max {
+		return next
+	}
 
-		time.Sleep(RelayInterval)
+	return max
+}
+
+func (r *EvmRelayer) onError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.LastError = err.Error()
+	r.status.LastErrorAt = time.Now()
+	r.status.ConsecutiveErrors++
+}
+
+func (r *EvmRelayer) onSuccess() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.ConsecutiveErrors = 0
+}
+
+// updateStatus updates status and persists it if required.
+func (r *EvmRelayer) updateStatus(update func(status *RelayerStatus)) {
+	r.mu.Lock()
+	update(&r.status)
+	status := r.status
+	r.mu.Unlock()
+
+	if r.StatusPath == "" {
+		return
+	}
+
+	if err := saveStatus(r.StatusPath, &status); err != nil {
+		r.logger.WithError(err).Warn("Failed to persist relayer status")
+	}
+}
+
+func (r *EvmRelayer) loadStatus() error {
+	data, err := os.ReadFile(r.StatusPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read relayer status file %v", r.StatusPath)
+	}
+
+	if err = json.Unmarshal(data, &r.status); err != nil {
+		return errors.Wrap(err, "Failed to unmarshal relayer status")
+	}
+
+	return nil
+}
+
+func saveStatus(path string, status *RelayerStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal relayer status")
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrapf(err, "Failed to write file %v", tmp)
 	}
+
+	return os.Rename(tmp, path)
 }
 
-func (r *EvmRelayer) relay(initialized bool) (relayed bool, err error) {
-	state, err := r.lightNode.State(nil)
+func (r *EvmRelayer) relay(ctx context.Context) (relayed bool, err error) {
+	state, err := r.lightNode.State(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return false, errors.WithMessage(err, "Failed to get light node state")
 	}
 
+	r.updatePendingGC(&state)
+
 	// initialize light node
-	if !initialized {
-		if err = r.initLightNode(&state); err != nil {
+	if !r.Status().Initialized {
+		if err = r.initLightNode(ctx, &state); err != nil {
 			return false, errors.WithMessage(err, "Failed to initialize light node")
 		}
 
+		r.updateStatus(func(status *RelayerStatus) { status.Initialized = true })
+
 		return true, nil
 	}
 
 	// relay pos block
-	if relayed, err = r.relayPosBlock(&state); err != nil {
+	if relayed, err = r.relayPosBlock(ctx, &state); err != nil {
 		return false, errors.WithMessage(err, "Failed to relay pos block")
 	}
 
@@ -103,24 +322,35 @@ func (r *EvmRelayer) relay(initialized bool) (relayed bool, err error) {
 	}
 
 	// garbage collect pow blocks
-	if relayed, err = r.removePowBlocks(&state); err != nil {
+	if relayed, err = r.removePowBlocks(ctx, &state); err != nil {
 		return false, errors.WithMessage(err, "Failed to remove pow blocks")
 	}
 
 	return relayed, nil
 }
 
-func (r *EvmRelayer) initLightNode(state *contract.ILightNodeState) error {
+func (r *EvmRelayer) updatePendingGC(state *contract.ILightNodeState) {
+	var pending uint64
+	if state.Blocks.Cmp(state.MaxBlocks) > 0 {
+		pending = new(big.Int).Sub(state.Blocks, state.MaxBlocks).Uint64()
+	}
+
+	r.mu.Lock()
+	r.status.PendingGC = pending
+	r.mu.Unlock()
+}
+
+func (r *EvmRelayer) initLightNode(ctx context.Context, state *contract.ILightNodeState) error {
 	if state.Epoch.Uint64() > 0 {
-		logrus.Debug("Light node already initialized")
+		r.logger.Debug("Light node already initialized")
 		return nil
 	}
 
 	if r.EpochFrom == 0 {
-		logrus.Fatal("epoch not configured to initialize light node")
+		return errors.New("epoch not configured to initialize light node")
 	}
 
-	logrus.WithField("epoch", r.EpochFrom).Debug("Begin to initialize light node")
+	r.logger.WithField("epoch", r.EpochFrom).Debug("Begin to initialize light node")
 
 	// get committee from previous epoch
 	lastEpochLedger, err := r.coreClient.Pos().GetLedgerInfoByEpoch(hexutil.Uint64(r.EpochFrom - 1))
@@ -130,7 +360,7 @@ func (r *EvmRelayer) initLightNode(state *contract.ILightNodeState) error {
 
 	committee, ok := contract.ConvertCommittee(lastEpochLedger)
 	if !ok {
-		logrus.Fatal("Committee not found")
+		return errors.Errorf("Committee not found in ledger of epoch %v", r.EpochFrom-1)
 	}
 
 	// get ledger of first round
@@ -140,14 +370,14 @@ func (r *EvmRelayer) initLightNode(state *contract.ILightNodeState) error {
 	}
 
 	if ledger == nil {
-		logrus.Fatal("Ledger not found")
+		return errors.Errorf("Ledger not found by epoch %v and round 1", r.EpochFrom)
 	}
 
 	if ledger.LedgerInfo.CommitInfo.Pivot == nil {
-		logrus.Fatal("Pivot in ledger is nil")
+		return errors.New("Pivot in ledger is nil")
 	}
 
-	tx, err := r.lightNode.Initialize(r.txOpts,
+	tx, err := r.lightNode.Initialize(r.transactOpts(ctx),
 		r.Admin, r.LedgerInfo, r.Verifier,
 		committee, contract.ConvertLedger(ledger),
 	)
@@ -155,24 +385,27 @@ func (r *EvmRelayer) initLightNode(state *contract.ILightNodeState) error {
 		return errors.WithMessage(err, "Failed to send transaction")
 	}
 
-	if err = r.waitForSuccess(tx.Hash()); err != nil {
+	if err = r.waitForSuccess(ctx, tx.Hash()); err != nil {
 		return err
 	}
 
-	logrus.WithFields(logrus.Fields{
+	pivot := uint64(ledger.LedgerInfo.CommitInfo.Pivot.Height)
+	r.onRelayed(r.EpochFrom, 1, pivot)
+
+	r.logger.WithFields(logrus.Fields{
 		"epoch": r.EpochFrom,
 		"round": 1,
-		"pivot": uint64(ledger.LedgerInfo.CommitInfo.Pivot.Height),
+		"pivot": pivot,
 	}).Info("Light node initialized")
 
 	return nil
 }
 
-func (r *EvmRelayer) relayPosBlock(state *contract.ILightNodeState) (bool, error) {
+func (r *EvmRelayer) relayPosBlock(ctx context.Context, state *contract.ILightNodeState) (bool, error) {
 	epoch := state.Epoch.Uint64()
 	round := state.Round.Uint64() + 1
-	if r.skippedRound > 0 {
-		round = r.skippedRound + 1
+	if status := r.Status(); status.SkippedEpoch == epoch && status.SkippedRound >= round {
+		round = status.SkippedRound + 1
 	}
 
 	committed, err := r.isCommitted(epoch, round)
@@ -181,11 +414,11 @@ func (r *EvmRelayer) relayPosBlock(state *contract.ILightNodeState) (bool, error
 	}
 
 	if !committed {
-		logrus.WithField("epoch", epoch).WithField("round", round).Debug("No pos block to relay")
+		r.logger.WithField("epoch", epoch).WithField("round", round).Debug("No pos block to relay")
 		return false, nil
 	}
 
-	logrus.WithField("epoch", epoch).WithField("round", round).Debug("Begin to relay pos block")
+	r.logger.WithField("epoch", epoch).WithField("round", round).Debug("Begin to relay pos block")
 
 	ledger, err := r.coreClient.Pos().GetLedgerInfoByEpochAndRound(hexutil.Uint64(epoch), hexutil.Uint64(round))
 	if err != nil {
@@ -194,8 +427,8 @@ func (r *EvmRelayer) relayPosBlock(state *contract.ILightNodeState) (bool, error
 
 	// no ledger in round, just skip it
 	if ledger == nil {
-		logrus.WithField("epoch", epoch).WithField("round", round).Debug("No ledger info in this round")
-		r.skippedRound = round
+		r.logger.WithField("epoch", epoch).WithField("round", round).Debug("No ledger info in this round")
+		r.onSkipped(epoch, round)
 		return true, nil
 	}
 
@@ -204,33 +437,58 @@ func (r *EvmRelayer) relayPosBlock(state *contract.ILightNodeState) (bool, error
 	// both committee and pow pivot block unchanged
 	if ledger.LedgerInfo.CommitInfo.NextEpochState == nil {
 		if pivot == nil || uint64(pivot.Height) <= state.FinalizedBlockNumber.Uint64() {
-			logrus.WithField("epoch", epoch).WithField("round", round).Debug("Pos block pivot not changed")
-			r.skippedRound = round
+			r.logger.WithField("epoch", epoch).WithField("round", round).Debug("Pos block pivot not changed")
+			r.onSkipped(epoch, round)
 			return true, nil
 		}
 	}
 
 	// update committee or pivot block
-	tx, err := r.lightNode.RelayPOS(r.txOpts, contract.ConvertLedger(ledger))
+	tx, err := r.lightNode.RelayPOS(r.transactOpts(ctx), contract.ConvertLedger(ledger))
 	if err != nil {
 		return false, errors.WithMessage(err, "Failed to send transaction")
 	}
 
-	if err = r.waitForSuccess(tx.Hash()); err != nil {
+	if err = r.waitForSuccess(ctx, tx.Hash()); err != nil {
 		return false, err
 	}
 
-	logrus.WithFields(logrus.Fields{
+	var pivotHeight uint64
+	if pivot != nil {
+		pivotHeight = uint64(pivot.Height)
+	}
+
+	r.onRelayed(epoch, round, pivotHeight)
+
+	r.logger.WithFields(logrus.Fields{
 		"epoch": epoch,
 		"round": round,
-		"pivot": uint64(pivot.Height),
+		"pivot": pivotHeight,
 	}).Info("Succeeded to relay pos block")
 
-	r.skippedRound = 0
-
 	return true, nil
 }
 
+func (r *EvmRelayer) onSkipped(epoch, round uint64) {
+	r.updateStatus(func(status *RelayerStatus) {
+		status.SkippedEpoch = epoch
+		status.SkippedRound = round
+	})
+}
+
+func (r *EvmRelayer) onRelayed(epoch, round, pivot uint64) {
+	r.updateStatus(func(status *RelayerStatus) {
+		status.LastRelayedEpoch = epoch
+		status.LastRelayedRound = round
+		if pivot > 0 {
+			status.LastRelayedPivot = pivot
+		}
+		status.LastRelayedAt = time.Now()
+		status.SkippedEpoch = 0
+		status.SkippedRound = 0
+	})
+}
+
 func (r *EvmRelayer) isCommitted(epoch, round uint64) (bool, error) {
 	status, err := r.coreClient.Pos().GetStatus()
 	if err != nil {
@@ -243,10 +501,10 @@ func (r *EvmRelayer) isCommitted(epoch, round uint64) (bool, error) {
 	}
 
 	if block == nil {
-		logrus.Fatal("Latest committed PoS block is nil")
+		return false, errors.New("Latest committed PoS block is nil")
 	}
 
-	logrus.WithFields(logrus.Fields{
+	r.logger.WithFields(logrus.Fields{
 		"epoch": uint64(block.Epoch),
 		"round": uint64(block.Round),
 	}).Debug("Latest committed block found")
@@ -268,24 +526,24 @@ func (r *EvmRelayer) RelayPoWBlocks(headers [][]byte) error {
 		return errors.WithMessage(err, "Failed to send transaction")
 	}
 
-	return r.waitForSuccess(tx.Hash())
+	return r.waitForSuccess(context.Background(), tx.Hash())
 }
 
-func (r *EvmRelayer) removePowBlocks(state *contract.ILightNodeState) (bool, error) {
+func (r *EvmRelayer) removePowBlocks(ctx context.Context, state *contract.ILightNodeState) (bool, error) {
 	if state.Blocks.Cmp(state.MaxBlocks) <= 0 {
 		return false, nil
 	}
 
-	tx, err := r.lightNode.RemoveBlockHeader(r.txOpts, big.NewInt(r.GcLimits))
+	tx, err := r.lightNode.RemoveBlockHeader(r.transactOpts(ctx), big.NewInt(r.GcLimits))
 	if err != nil {
 		return false, errors.WithMessage(err, "Failed to send transaction")
 	}
 
-	if err = r.waitForSuccess(tx.Hash()); err != nil {
+	if err = r.waitForSuccess(ctx, tx.Hash()); err != nil {
 		return false, err
 	}
 
-	logrus.WithFields(logrus.Fields{
+	r.logger.WithFields(logrus.Fields{
 		"blocks": state.Blocks,
 		"max":    state.MaxBlocks,
 	}).Debug("Succeeded to remove PoW blocks")
@@ -293,15 +551,26 @@ func (r *EvmRelayer) removePowBlocks(state *contract.ILightNodeState) (bool, err
 	return true, nil
 }
 
-func (r *EvmRelayer) waitForSuccess(txHash common.Hash) error {
-	time.Sleep(3 * time.Second)
+func (r *EvmRelayer) transactOpts(ctx context.Context) *bind.TransactOpts {
+	opts := *r.txOpts
+	opts.Context = ctx
+	return &opts
+}
+
+func (r *EvmRelayer) waitForSuccess(ctx context.Context, txHash common.Hash) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
 	for {
-		time.Sleep(time.Second)
+		select {
+		case <-ctx.Done():
+			return errors.WithMessagef(ctx.Err(), "Aborted to wait for receipt of transaction %v", txHash)
+		case <-ticker.C:
+		}
 
 		receipt, err := r.relayerClient.Eth.TransactionReceipt(txHash)
 		if err != nil {
-			logrus.WithError(err).Warn("Failed to wait for receipt")
+			r.logger.WithError(err).Warn("Failed to wait for receipt")
 		} else if receipt != nil {
 			if uint8(*receipt.Status) == uint8(enums.EVM_SPACE_SUCCESS) {
 				return nil
@@ -0,0 +1,36 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, nextBackoff(time.Second, time.Minute))
+	assert.Equal(t, 32*time.Second, nextBackoff(16*time.Second, time.Minute))
+	assert.Equal(t, time.Minute, nextBackoff(32*time.Second, time.Minute))
+	assert.Equal(t, time.Minute, nextBackoff(time.Minute, time.Minute))
+}
+
+func TestRelayerStatusPersistence(t *testing.T) {
+	path := t.TempDir() + "/status.json"
+
+	status := RelayerStatus{
+		Running:          true,
+		Initialized:      true,
+		LastRelayedEpoch: 10,
+		LastRelayedRound: 5,
+		SkippedEpoch:     10,
+		SkippedRound:     8,
+	}
+	assert.NoError(t, saveStatus(path, &status))
+
+	relayer := EvmRelayer{EvmRelayConfig: EvmRelayConfig{StatusPath: path}}
+	assert.NoError(t, relayer.loadStatus())
+
+	// running states are not persisted
+	status.Running, status.Initialized = false, false
+	assert.Equal(t, status, relayer.Status())
+}
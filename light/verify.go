@@ -0,0 +1,124 @@
+package light
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/light/mpt"
+	"github.com/Conflux-Chain/go-conflux-sdk/light/primitives"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidReceiptProof     = errors.New("invalid receipt proof")
+	ErrInvalidTransactionProof = errors.New("invalid transaction proof")
+)
+
+// VerifyReceipt verifies the receipt against the DeferredReceiptsRoot of a trusted header by the proof returned by
+// Debug().GetEpochReceiptProofByTransaction.
+//
+// The header should be the pivot block that defers the execution result of the epoch that receipt executed in,
+// namely the pivot block of epoch `receipt.EpochNumber + 5`. Note, the receipt should contain all fields to encode,
+// e.g. AccumulatedGasUsed, which is returned by Debug().GetEpochReceipts.
+//
+// The epochBlockHashes is the hashes of blocks in the epoch in execution order, which is returned by
+// GetBlocksByEpoch, and is used to bind the block index in proof to the block that receipt packed in.
+func VerifyReceipt(receipt *types.TransactionReceipt, proof *types.EpochReceiptProof, header *types.BlockHeader, epochBlockHashes []types.Hash) error {
+	if receipt == nil || proof == nil || header == nil {
+		return errors.New("receipt, proof and header should not be nil")
+	}
+
+	expectedBlockIndexKey, err := epochBlockIndexKey(receipt.BlockHash, epochBlockHashes)
+	if err != nil {
+		return errors.WithMessage(ErrInvalidReceiptProof, err.Error())
+	}
+
+	if receipt.EpochNumber != nil && header.EpochNumber != nil {
+		expected := uint64(*receipt.EpochNumber) + deferredExecutionEpochs
+		if actual := header.EpochNumber.ToInt().Uint64(); actual != expected {
+			return errors.WithMessagef(ErrInvalidReceiptProof, "header epoch mismatch, expected = %v, actual = %v", expected, actual)
+		}
+	}
+
+	// verify the receipts root of block against the epoch receipts root
+	epochReceiptsRoot := *header.DeferredReceiptsRoot.ToCommonHash()
+	blockNodes, blockIndexKey, blockReceiptsRoot, err := mpt.ConvertTrieProof(epochReceiptsRoot, proof.BlockIndexProof)
+	if err != nil {
+		return errors.WithMessage(ErrInvalidReceiptProof, err.Error())
+	}
+
+	if len(blockReceiptsRoot) != common.HashLength {
+		return errors.WithMessagef(ErrInvalidReceiptProof, "invalid block receipts root %x", blockReceiptsRoot)
+	}
+
+	if !bytes.Equal(blockIndexKey, expectedBlockIndexKey) {
+		return errors.WithMessagef(ErrInvalidReceiptProof, "block index mismatch, expected = %x, actual = %x", expectedBlockIndexKey, blockIndexKey)
+	}
+
+	if !mpt.Prove(epochReceiptsRoot, blockIndexKey, blockReceiptsRoot, blockNodes) {
+		return errors.WithMessage(ErrInvalidReceiptProof, "failed to prove block index")
+	}
+
+	// verify the receipt against the receipts root of block
+	receiptsRoot := common.BytesToHash(blockReceiptsRoot)
+	receiptNodes, receiptKey, receiptValue, err := mpt.ConvertTrieProof(receiptsRoot, proof.BlockReceiptProof)
+	if err != nil {
+		return errors.WithMessage(ErrInvalidReceiptProof, err.Error())
+	}
+
+	if index := new(big.Int).SetBytes(receiptKey); !index.IsUint64() || index.Uint64() != uint64(receipt.Index) {
+		return errors.WithMessagef(ErrInvalidReceiptProof, "receipt index mismatch, expected = %v, actual = %v", uint64(receipt.Index), index)
+	}
+
+	encoded := primitives.MustRLPEncodeReceipt(receipt)
+	if !bytes.Equal(encoded, receiptValue) {
+		return errors.WithMessage(ErrInvalidReceiptProof, "receipt mismatch")
+	}
+
+	if !mpt.Prove(receiptsRoot, receiptKey, encoded, receiptNodes) {
+		return errors.WithMessage(ErrInvalidReceiptProof, "failed to prove receipt")
+	}
+
+	return nil
+}
+
+// epochBlockIndexKey returns the key of block in the epoch receipts trie, which is the index of block in epoch.
+func epochBlockIndexKey(blockHash types.Hash, epochBlockHashes []types.Hash) ([]byte, error) {
+	for i, v := range epochBlockHashes {
+		if strings.EqualFold(v.String(), blockHash.String()) {
+			return mpt.IndexToKey(i, len(epochBlockHashes)), nil
+		}
+	}
+
+	return nil, errors.Errorf("block %v not found in epoch", blockHash)
+}
+
+// VerifyTransactionProof verifies the transaction `txHash` is packed in the block of a trusted header by the proof
+// returned by CreateTransactionProof.
+func VerifyTransactionProof(header *types.BlockHeader, txHash common.Hash, proof *TransactionProof) error {
+	if header == nil || proof == nil {
+		return errors.New("header and proof should not be nil")
+	}
+
+	if proof.BlockHash != header.Hash {
+		return errors.WithMessagef(ErrInvalidTransactionProof, "block hash mismatch, expected = %v, actual = %v", header.Hash, proof.BlockHash)
+	}
+
+	root := *header.TransactionsRoot.ToCommonHash()
+	if proof.TransactionsRoot != root {
+		return errors.WithMessagef(ErrInvalidTransactionProof, "transactions root mismatch, expected = %v, actual = %v", root, proof.TransactionsRoot)
+	}
+
+	if index := new(big.Int).SetBytes(proof.Key); !index.IsInt64() || index.Int64() != int64(proof.Index) {
+		return errors.WithMessagef(ErrInvalidTransactionProof, "transaction index mismatch, expected = %v, actual = %v", proof.Index, index)
+	}
+
+	if !mpt.Prove(root, proof.Key, txHash.Bytes(), proof.Proof) {
+		return errors.WithMessage(ErrInvalidTransactionProof, "failed to prove transaction")
+	}
+
+	return nil
+}
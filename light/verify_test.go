@@ -0,0 +1,35 @@
+package light
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochBlockIndexKey(t *testing.T) {
+	var hashes []types.Hash
+	for i := 0; i < 300; i++ {
+		hashes = append(hashes, types.Hash(fmt.Sprintf("0x%064x", i)))
+	}
+
+	key, err := epochBlockIndexKey(hashes[1], hashes[:3])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, key)
+
+	key, err = epochBlockIndexKey(hashes[257], hashes)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 1}, key)
+
+	_, err = epochBlockIndexKey(hashes[3], hashes[:3])
+	assert.Error(t, err)
+}
+
+func TestVerifyReceiptBlockNotInEpoch(t *testing.T) {
+	receipt := &types.TransactionReceipt{BlockHash: types.Hash("0x01")}
+	header := &types.BlockHeader{}
+
+	err := VerifyReceipt(receipt, &types.EpochReceiptProof{}, header, []types.Hash{"0x02"})
+	assert.ErrorIs(t, err, ErrInvalidReceiptProof)
+}
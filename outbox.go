@@ -0,0 +1,319 @@
+package sdk
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// OutboxStatus is the status of transaction in Outbox.
+type OutboxStatus string
+
+const (
+	OutboxStatusSigned    OutboxStatus = "signed"    // signed but not sent successfully yet
+	OutboxStatusSent      OutboxStatus = "sent"      // sent to node and waiting for receipt
+	OutboxStatusConfirmed OutboxStatus = "confirmed" // receipt is available
+	OutboxStatusFailed    OutboxStatus = "failed"    // rejected by node, e.g. invalid nonce or insufficient balance
+	OutboxStatusDropped   OutboxStatus = "dropped"   // the nonce is used by another transaction
+)
+
+// IsFinal returns true if the transaction will not be rebroadcasted.
+func (s OutboxStatus) IsFinal() bool {
+	return s == OutboxStatusConfirmed || s == OutboxStatusFailed || s == OutboxStatusDropped
+}
+
+// OutboxEntry is a signed transaction tracked by Outbox.
+type OutboxEntry struct {
+	Hash     types.Hash
+	From     types.Address
+	Nonce    *hexutil.Big
+	Unsigned types.UnsignedTransaction
+	Raw      hexutil.Bytes
+	Status   OutboxStatus
+
+	SendCount int
+	SendError string // the last error of sending transaction
+
+	// available when confirmed
+	EpochNumber   *hexutil.Uint64
+	OutcomeStatus *hexutil.Uint64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OutboxFilter filters the entries of Outbox, and the empty fields are ignored.
+type OutboxFilter struct {
+	From     *types.Address
+	Statuses []OutboxStatus
+}
+
+func (f *OutboxFilter) match(entry *OutboxEntry) bool {
+	if f.From != nil && f.From.String() != entry.From.String() {
+		return false
+	}
+
+	if len(f.Statuses) == 0 {
+		return true
+	}
+
+	for _, v := range f.Statuses {
+		if v == entry.Status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Outbox persists the signed transactions before sending, so that they could be tracked and rebroadcasted
+// after the service restarted.
+//
+// Set it to client by Client.SetOutbox, then transactions sent by Client.SendTransaction and BulkSender are
+// recorded automatically. Call Rebroadcast on startup to resend the unconfirmed ones.
+type Outbox struct {
+	client ClientOperator
+	store  OutboxStore
+	mu     sync.Mutex
+}
+
+// NewOutbox creates an Outbox which persists entries as files in the directory.
+func NewOutbox(client ClientOperator, dir string) (*Outbox, error) {
+	store, err := NewFileOutboxStore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOutboxWithStore(client, store), nil
+}
+
+// NewOutboxWithStore creates an Outbox with the specified store, e.g. NewMemoryOutboxStore for testing.
+func NewOutboxWithStore(client ClientOperator, store OutboxStore) *Outbox {
+	return &Outbox{client: client, store: store}
+}
+
+// Record persists the signed transaction before sending, and returns the existing entry if the transaction
+// has been recorded.
+func (o *Outbox) Record(tx types.UnsignedTransaction, rawData []byte) (*OutboxEntry, error) {
+	if tx.From == nil || tx.Nonce == nil {
+		return nil, errors.New("from and nonce of transaction are required")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	hash := RawTxHash(rawData)
+
+	existing, err := o.store.Get(hash)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get outbox entry %v", hash)
+	}
+
+	if existing != nil {
+		return existing, nil
+	}
+
+	now := time.Now()
+	entry := OutboxEntry{
+		Hash:      hash,
+		From:      *tx.From,
+		Nonce:     tx.Nonce,
+		Unsigned:  tx,
+		Raw:       rawData,
+		Status:    OutboxStatusSigned,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = o.store.Put(&entry); err != nil {
+		return nil, errors.WithMessagef(err, "failed to put outbox entry %v", hash)
+	}
+
+	return &entry, nil
+}
+
+// MarkSent updates the entry by the result of sending transaction. The transaction which already exists
+// in node is regarded as sent.
+//
+// Only the transaction rejected by node is marked as failed. For the transport errors, e.g. timeout or connection
+// refused, the status is kept so that the transaction will be rebroadcasted, since it may have been received by node.
+func (o *Outbox) MarkSent(hash types.Hash, sendErr error) error {
+	return o.update(hash, func(entry *OutboxEntry) {
+		entry.SendCount++
+
+		if sendErr == nil || IsTxAlreadyExistError(sendErr) {
+			entry.SendError = ""
+			if !entry.Status.IsFinal() {
+				entry.Status = OutboxStatusSent
+			}
+			return
+		}
+
+		entry.SendError = sendErr.Error()
+
+		if _, err := utils.ToRpcError(sendErr); err != nil {
+			return
+		}
+
+		if entry.Status == OutboxStatusSigned || entry.Status == OutboxStatusSent {
+			entry.Status = OutboxStatusFailed
+		}
+	})
+}
+
+// Get returns the entry of transaction hash, and nil if not found.
+func (o *Outbox) Get(hash types.Hash) (*OutboxEntry, error) {
+	return o.store.Get(hash)
+}
+
+// List returns the entries matched by filter, ordered by sender and nonce.
+func (o *Outbox) List(filter OutboxFilter) ([]*OutboxEntry, error) {
+	entries, err := o.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*OutboxEntry
+	for _, v := range entries {
+		if filter.match(v) {
+			result = append(result, v)
+		}
+	}
+
+	return result, nil
+}
+
+// Unconfirmed returns the entries that are signed or sent but not confirmed yet.
+func (o *Outbox) Unconfirmed() ([]*OutboxEntry, error) {
+	return o.List(OutboxFilter{Statuses: []OutboxStatus{OutboxStatusSigned, OutboxStatusSent}})
+}
+
+// Remove removes the entry of transaction hash, e.g. the confirmed ones that no longer need to be tracked.
+func (o *Outbox) Remove(hash types.Hash) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.store.Delete(hash)
+}
+
+// Sync updates the status of unconfirmed and failed entries by receipts. An unconfirmed entry is marked as dropped
+// if not found in node and its nonce has been used by another transaction. The failed entries are checked too,
+// since the transaction may have been received by node before rejected when rebroadcasted.
+func (o *Outbox) Sync() error {
+	entries, err := o.List(OutboxFilter{Statuses: []OutboxStatus{OutboxStatusSigned, OutboxStatusSent, OutboxStatusFailed}})
+	if err != nil {
+		return err
+	}
+
+	nextNonces := make(map[string]*hexutil.Big)
+
+	for _, entry := range entries {
+		receipt, err := o.client.GetTransactionReceipt(entry.Hash)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get receipt of %v", entry.Hash)
+		}
+
+		if receipt != nil {
+			err = o.update(entry.Hash, func(e *OutboxEntry) {
+				e.Status = OutboxStatusConfirmed
+				e.EpochNumber = receipt.EpochNumber
+				e.OutcomeStatus = (*hexutil.Uint64)(&receipt.OutcomeStatus)
+			})
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if entry.Status == OutboxStatusFailed {
+			continue
+		}
+
+		from := entry.From.String()
+		if _, ok := nextNonces[from]; !ok {
+			if nextNonces[from], err = o.client.GetNextNonce(entry.From); err != nil {
+				return errors.WithMessagef(err, "failed to get next nonce of %v", from)
+			}
+		}
+
+		if entry.Nonce.ToInt().Cmp(nextNonces[from].ToInt()) >= 0 {
+			continue
+		}
+
+		tx, err := o.client.GetTransactionByHash(entry.Hash)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get transaction %v", entry.Hash)
+		}
+
+		// packed but receipt not available yet
+		if tx != nil {
+			continue
+		}
+
+		if err = o.update(entry.Hash, func(e *OutboxEntry) { e.Status = OutboxStatusDropped }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rebroadcast syncs the status of entries and resends the unconfirmed ones, and returns the resent entries
+// with the send error if any. It should be called on startup to recover the transactions not sent or not
+// confirmed before the service stopped.
+func (o *Outbox) Rebroadcast() ([]*OutboxEntry, error) {
+	if err := o.Sync(); err != nil {
+		return nil, errors.WithMessage(err, "failed to sync outbox")
+	}
+
+	entries, err := o.Unconfirmed()
+	if err != nil {
+		return nil, err
+	}
+
+	var resent []*OutboxEntry
+
+	for _, entry := range entries {
+		_, sendErr := o.client.SendRawTransaction(entry.Raw)
+		if err := o.MarkSent(entry.Hash, sendErr); err != nil {
+			return nil, err
+		}
+
+		updated, err := o.store.Get(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		resent = append(resent, updated)
+	}
+
+	return resent, nil
+}
+
+func (o *Outbox) update(hash types.Hash, fn func(entry *OutboxEntry)) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, err := o.store.Get(hash)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get outbox entry %v", hash)
+	}
+
+	if entry == nil {
+		return errors.Errorf("outbox entry %v not found", hash)
+	}
+
+	fn(entry)
+	entry.UpdatedAt = time.Now()
+
+	if err = o.store.Put(entry); err != nil {
+		return errors.WithMessagef(err, "failed to put outbox entry %v", hash)
+	}
+
+	return nil
+}
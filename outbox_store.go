@@ -0,0 +1,210 @@
+package sdk
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// OutboxStore persists the entries of Outbox.
+type OutboxStore interface {
+	// Put inserts or updates the entry by transaction hash.
+	Put(entry *OutboxEntry) error
+	// Get returns the entry of transaction hash, and nil if not found.
+	Get(hash types.Hash) (*OutboxEntry, error)
+	// List returns all the entries ordered by sender and nonce.
+	List() ([]*OutboxEntry, error)
+	// Delete removes the entry of transaction hash.
+	Delete(hash types.Hash) error
+}
+
+// MemoryOutboxStore keeps the entries of Outbox in memory, which is useful for testing.
+type MemoryOutboxStore struct {
+	mu      sync.RWMutex
+	entries map[types.Hash]OutboxEntry
+}
+
+// NewMemoryOutboxStore creates an in-memory OutboxStore.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{entries: make(map[types.Hash]OutboxEntry)}
+}
+
+func (s *MemoryOutboxStore) Put(entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.Hash] = *entry
+	return nil
+}
+
+func (s *MemoryOutboxStore) Get(hash types.Hash) (*OutboxEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+func (s *MemoryOutboxStore) List() ([]*OutboxEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*OutboxEntry, 0, len(s.entries))
+	for k := range s.entries {
+		entry := s.entries[k]
+		entries = append(entries, &entry)
+	}
+
+	sortOutboxEntries(entries)
+
+	return entries, nil
+}
+
+func (s *MemoryOutboxStore) Delete(hash types.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, hash)
+	return nil
+}
+
+// FileOutboxStore keeps each entry of Outbox as a json file named by transaction hash in a directory.
+type FileOutboxStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileOutboxStore creates an OutboxStore in the directory, and the directory will be created if not exists.
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create outbox directory %v", dir)
+	}
+
+	return &FileOutboxStore{dir: dir}, nil
+}
+
+func (s *FileOutboxStore) Put(entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal outbox entry %v", entry.Hash)
+	}
+
+	// write to a temporary file and rename it to avoid broken file if crashed during writing
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to write outbox entry %v", entry.Hash)
+	}
+
+	if err = os.Rename(tmp.Name(), s.path(entry.Hash)); err != nil {
+		return errors.Wrapf(err, "failed to save outbox entry %v", entry.Hash)
+	}
+
+	return nil
+}
+
+func (s *FileOutboxStore) Get(hash types.Hash) (*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.path(hash))
+}
+
+func (s *FileOutboxStore) List() ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "0x*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list outbox entries")
+	}
+
+	var entries []*OutboxEntry
+	for _, file := range files {
+		entry, err := s.read(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	sortOutboxEntries(entries)
+
+	return entries, nil
+}
+
+func (s *FileOutboxStore) Delete(hash types.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(hash)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to delete outbox entry %v", hash)
+	}
+
+	return nil
+}
+
+func (s *FileOutboxStore) path(hash types.Hash) string {
+	return filepath.Join(s.dir, strings.ToLower(hash.String())+".json")
+}
+
+func (s *FileOutboxStore) read(file string) (*OutboxEntry, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read outbox entry %v", file)
+	}
+
+	var entry OutboxEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal outbox entry %v", file)
+	}
+
+	return &entry, nil
+}
+
+func sortOutboxEntries(entries []*OutboxEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		fromI, fromJ := entries[i].From.String(), entries[j].From.String()
+		if fromI != fromJ {
+			return fromI < fromJ
+		}
+
+		if c := entries[i].Nonce.ToInt().Cmp(entries[j].Nonce.ToInt()); c != 0 {
+			return c < 0
+		}
+
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+}
@@ -0,0 +1,155 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/internal/testutil"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOutboxTx(nonce uint64) types.UnsignedTransaction {
+	tx := types.UnsignedTransaction{To: &simulateContract}
+	tx.From = &simulateUser
+	tx.Nonce = types.NewBigInt(nonce)
+	tx.Value = types.NewBigInt(1)
+	return tx
+}
+
+func TestOutboxStores(t *testing.T) {
+	fileStore, err := NewFileOutboxStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, store := range []OutboxStore{NewMemoryOutboxStore(), fileStore} {
+		outbox := NewOutboxWithStore(nil, store)
+
+		second, err := outbox.Record(newOutboxTx(2), []byte{2})
+		assert.NoError(t, err)
+		first, err := outbox.Record(newOutboxTx(1), []byte{1})
+		assert.NoError(t, err)
+
+		// dedupe by hash
+		dup, err := outbox.Record(newOutboxTx(1), []byte{1})
+		assert.NoError(t, err)
+		assert.Equal(t, first.CreatedAt.UnixNano(), dup.CreatedAt.UnixNano())
+
+		entries, err := outbox.List(OutboxFilter{From: &simulateUser})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, first.Hash, entries[0].Hash)
+		assert.Equal(t, second.Hash, entries[1].Hash)
+		assert.Equal(t, simulateContract.String(), entries[0].Unsigned.To.String())
+		assert.Equal(t, OutboxStatusSigned, entries[0].Status)
+
+		assert.NoError(t, outbox.MarkSent(first.Hash, nil))
+		assert.Error(t, outbox.MarkSent("0x01", nil))
+
+		// status kept for transport error
+		assert.NoError(t, outbox.MarkSent(second.Hash, errors.New("i/o timeout")))
+		entry, err := outbox.Get(second.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxStatusSigned, entry.Status)
+		assert.Equal(t, "i/o timeout", entry.SendError)
+
+		assert.NoError(t, outbox.MarkSent(second.Hash, &utils.RpcError{Code: -32003, Message: "insufficient balance"}))
+		entry, err = outbox.Get(second.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxStatusFailed, entry.Status)
+		assert.Contains(t, entry.SendError, "insufficient balance")
+		assert.Equal(t, 2, entry.SendCount)
+
+		unconfirmed, err := outbox.Unconfirmed()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(unconfirmed))
+		assert.Equal(t, OutboxStatusSent, unconfirmed[0].Status)
+
+		assert.NoError(t, outbox.Remove(first.Hash))
+		entry, err = outbox.Get(first.Hash)
+		assert.NoError(t, err)
+		assert.Nil(t, entry)
+	}
+}
+
+func TestOutboxRebroadcast(t *testing.T) {
+	provider := testutil.NewFakeProvider(map[string]interface{}{
+		"cfx_getTransactionReceipt": nil,
+		"cfx_getTransactionByHash":  nil,
+		"cfx_getNextNonce":          "0x2",
+		"cfx_sendRawTransaction":    "0x0000000000000000000000000000000000000000000000000000000000000001",
+	})
+	client := newFakeClient(t, provider)
+
+	am := NewPrivatekeyAccountManager([]string{"0x0123456789012345678901234567890123456789012345678901234567890123"}, 1029)
+	client.SetAccountManager(am)
+	from, err := am.GetDefault()
+	assert.NoError(t, err)
+
+	// copies of client share the outbox set later
+	copied := *client
+
+	dir := t.TempDir()
+	outbox, err := NewOutbox(client, dir)
+	assert.NoError(t, err)
+	client.SetOutbox(outbox)
+	assert.Equal(t, outbox, copied.Outbox())
+
+	send := func(nonce uint64) types.Hash {
+		tx := newOutboxTx(nonce)
+		tx.From = from
+		tx.GasPrice = types.NewBigInt(1)
+		tx.Gas = types.NewBigInt(21000)
+		tx.StorageLimit = types.NewUint64(0)
+		tx.EpochHeight = types.NewUint64(1)
+
+		_, err := client.SendTransaction(tx)
+		assert.NoError(t, err)
+
+		entries, err := outbox.List(OutboxFilter{})
+		assert.NoError(t, err)
+		for _, v := range entries {
+			if v.Nonce.ToInt().Uint64() == nonce {
+				return v.Hash
+			}
+		}
+
+		t.Fatalf("transaction with nonce %v not recorded", nonce)
+		return ""
+	}
+
+	dropped, pending := send(1), send(2)
+
+	// reopen after restarted
+	outbox, err = NewOutbox(client, dir)
+	assert.NoError(t, err)
+
+	provider.Errors["cfx_sendRawTransaction"] = errors.New("tx already exist")
+
+	resent, err := outbox.Rebroadcast()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resent))
+	assert.Equal(t, pending, resent[0].Hash)
+	assert.Equal(t, OutboxStatusSent, resent[0].Status)
+	assert.Equal(t, 2, resent[0].SendCount)
+
+	entry, err := outbox.Get(dropped)
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxStatusDropped, entry.Status)
+
+	// rejected by node when rebroadcasted, but packed before
+	provider.Errors["cfx_sendRawTransaction"] = &utils.RpcError{Code: -32602, Message: "too stale nonce"}
+	assert.NoError(t, outbox.MarkSent(pending, provider.Errors["cfx_sendRawTransaction"]))
+
+	entry, err = outbox.Get(pending)
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxStatusFailed, entry.Status)
+
+	provider.Results["cfx_getTransactionReceipt"] = map[string]interface{}{"epochNumber": "0x10", "outcomeStatus": "0x0"}
+	assert.NoError(t, outbox.Sync())
+
+	entry, err = outbox.Get(pending)
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxStatusConfirmed, entry.Status)
+	assert.Equal(t, uint64(16), uint64(*entry.EpochNumber))
+}
@@ -3,12 +3,15 @@ package sdk
 import (
 	"crypto/ecdsa"
 	"encoding/hex"
+	"math/big"
 	"sync"
 	"time"
 
 	"github.com/Conflux-Chain/go-conflux-sdk/types"
 	sdkErrors "github.com/Conflux-Chain/go-conflux-sdk/types/errors"
 	"github.com/Conflux-Chain/go-conflux-sdk/utils/addressutil"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/openweb3/go-sdk-common/privatekeyhelper"
 	"github.com/pkg/errors"
@@ -188,6 +191,37 @@ func (p *PrivatekeyAccountManager) Sign(tx types.UnsignedTransaction, passphrase
 	return v, r, s, nil
 }
 
+// ListESpace returns the eSpace addresses of all accounts, which are derived from the same private keys
+func (p *PrivatekeyAccountManager) ListESpace() []common.Address {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var result []common.Address
+	for _, address := range p.addresses {
+		result = append(result, crypto.PubkeyToAddress(p.accountsMap[address.GetHexAddress()].PublicKey))
+	}
+	return result
+}
+
+// SignESpaceTransaction signs eSpace tx by the key of eSpace address from
+func (p *PrivatekeyAccountManager) SignESpaceTransaction(from common.Address, tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, key := range p.accountsMap {
+		if crypto.PubkeyToAddress(key.PublicKey) != from {
+			continue
+		}
+
+		signed, err := ethtypes.SignTx(tx, ethtypes.LatestSignerForChainID(chainID), key)
+		if err != nil {
+			return nil, errors.Wrap(err, errMsgSignTx)
+		}
+		return signed, nil
+	}
+	return nil, errors.Errorf("account %v not found", from)
+}
+
 func (p *PrivatekeyAccountManager) pushAccount(key *ecdsa.PrivateKey) types.Address {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -1,9 +1,37 @@
 package sdk
 
 import (
+	"math/big"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPrivatekeyAccountManagerInterface(t *testing.T) {
 	var _ AccountManagerOperator = NewPrivatekeyAccountManager(nil, 1)
+	var _ ESpaceSigner = NewPrivatekeyAccountManager(nil, 1)
+	var _ ESpaceSigner = &AccountManager{}
+}
+
+func TestPrivatekeyAccountManagerSignESpaceTransaction(t *testing.T) {
+	am := NewPrivatekeyAccountManager([]string{"0x0123456789012345678901234567890123456789012345678901234567890123"}, 1)
+
+	accounts := am.ListESpace()
+	assert.Equal(t, 1, len(accounts))
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx := ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)})
+
+	chainID := big.NewInt(71)
+	signed, err := am.SignESpaceTransaction(accounts[0], tx, chainID)
+	assert.NoError(t, err)
+
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, accounts[0], sender)
+
+	_, err = am.SignESpaceTransaction(to, tx, chainID)
+	assert.Error(t, err)
 }
@@ -0,0 +1,272 @@
+package sdk
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/unit"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils/abiutil"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// SimulationReport is the preflight report of transaction before signing.
+type SimulationReport struct {
+	Estimate *types.Estimate // nil if failed to estimate
+
+	GasLimit     *big.Int
+	GasPrice     *big.Int // gas price of legacy transaction, or max fee per gas of 1559 transaction
+	StorageLimit uint64
+
+	GasFee            *big.Int   // GasLimit * GasPrice
+	StorageCollateral *big.Int   // StorageLimit * constants.CollateralPerStorageByte
+	TotalCost         *big.Int   // value and the gas fee and storage collateral that not sponsored
+	TotalCostCFX      string     // e.g. "0.0625 CFX"
+	Cost              *TotalCost // breakdown of the total cost, see EstimateTotalCost
+
+	GasSponsored        bool
+	CollateralSponsored bool
+	IsBalanceEnough     bool
+
+	ReturnData   hexutil.Bytes
+	RevertReason string
+
+	Warnings []string
+}
+
+// Succeeded returns true if the transaction is expected to be executed successfully without warnings.
+func (r *SimulationReport) Succeeded() bool {
+	return r.RevertReason == "" && len(r.Warnings) == 0
+}
+
+// DecodeReturn decodes the return data with the outputs of contract method.
+func (r *SimulationReport) DecodeReturn(contractABI *abi.ABI, method string) ([]interface{}, error) {
+	if r.RevertReason != "" {
+		return nil, errors.Errorf("transaction reverted: %v", r.RevertReason)
+	}
+
+	values, err := contractABI.Unpack(method, r.ReturnData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode return data of method %v", method)
+	}
+
+	return values, nil
+}
+
+func (r *SimulationReport) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Simulate runs the transaction against latest state without sending it, and reports the return value or
+// revert reason, the total cost in CFX, sponsor coverage and the warnings found, e.g. insufficient balance,
+// invalid nonce or epoch height, and mismatched network of addresses.
+//
+// The error is returned only if failed to request node, and problems of transaction are reported as warnings.
+func (client *Client) Simulate(tx types.UnsignedTransaction) (*SimulationReport, error) {
+	var report SimulationReport
+
+	if tx.From == nil {
+		return nil, errors.New("from address is required")
+	}
+
+	if err := client.checkNetwork(&tx, &report); err != nil {
+		return nil, err
+	}
+
+	callReq := new(types.CallRequest)
+	callReq.FillByUnsignedTx(&tx)
+
+	estimate, err := client.EstimateGasAndCollateral(*callReq)
+	if err != nil {
+		reason, ok := revertReason(err)
+		if !ok {
+			return nil, errors.Wrap(err, "failed to estimate gas and collateral")
+		}
+
+		report.RevertReason = reason
+	} else {
+		report.Estimate = &estimate
+	}
+
+	returnData, err := client.Call(*callReq, nil)
+	if err != nil {
+		reason, ok := revertReason(err)
+		if !ok {
+			return nil, errors.Wrap(err, "failed to call")
+		}
+
+		if report.RevertReason == "" {
+			report.RevertReason = reason
+		}
+	} else {
+		report.ReturnData = returnData
+	}
+
+	if err := client.simulateCost(&tx, &report); err != nil {
+		return nil, err
+	}
+
+	if err := client.checkNonce(&tx, &report); err != nil {
+		return nil, err
+	}
+
+	if err := client.checkEpochHeight(&tx, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (client *Client) checkNetwork(tx *types.UnsignedTransaction, report *SimulationReport) error {
+	networkID, err := client.GetNetworkID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get networkID")
+	}
+
+	if id := tx.From.GetNetworkID(); id != 0 && id != networkID {
+		report.warn("network ID %v of from address mismatch with client network ID %v", id, networkID)
+	}
+
+	if tx.To != nil {
+		if id := tx.To.GetNetworkID(); id != 0 && id != networkID {
+			report.warn("network ID %v of to address mismatch with client network ID %v", id, networkID)
+		}
+	}
+
+	if tx.ChainID != nil {
+		chainID, err := client.GetChainID()
+		if err != nil {
+			return errors.Wrap(err, "failed to get chainID")
+		}
+
+		if uint32(*tx.ChainID) != chainID {
+			report.warn("chain ID %v mismatch with client chain ID %v", *tx.ChainID, chainID)
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) simulateCost(tx *types.UnsignedTransaction, report *SimulationReport) error {
+	cost, err := client.totalCostOf(tx, report.Estimate)
+	if err != nil {
+		return err
+	}
+
+	report.Cost = cost
+	report.GasLimit, report.GasPrice, report.StorageLimit = cost.GasLimit, cost.GasPrice, cost.StorageLimit
+
+	if report.Estimate != nil {
+		if tx.Gas != nil && report.GasLimit.Cmp(report.Estimate.GasUsed.ToInt()) < 0 {
+			report.warn("gas limit %v is less than estimated gas used %v", report.GasLimit, report.Estimate.GasUsed)
+		}
+
+		if tx.StorageLimit != nil && new(big.Int).SetUint64(report.StorageLimit).Cmp(report.Estimate.StorageCollateralized.ToInt()) < 0 {
+			report.warn("storage limit %v is less than estimated storage collateralized %v", report.StorageLimit, report.Estimate.StorageCollateralized)
+		}
+	}
+
+	report.GasFee, report.StorageCollateral = cost.MaxGasFee, cost.StorageCollateral
+	report.GasSponsored, report.CollateralSponsored = cost.GasSponsored, cost.CollateralSponsored
+	report.TotalCost = cost.WorstCase
+
+	report.TotalCostCFX = formatCFX(report.TotalCost)
+
+	balance, err := client.GetBalance(*tx.From)
+	if err != nil {
+		return errors.Wrap(err, "failed to get balance")
+	}
+
+	report.IsBalanceEnough = balance.ToInt().Cmp(report.TotalCost) >= 0
+	if !report.IsBalanceEnough {
+		report.warn("balance %v is not enough for total cost %v", unit.NewDrip(balance.ToInt()), report.TotalCostCFX)
+	}
+
+	return nil
+}
+
+func (client *Client) checkNonce(tx *types.UnsignedTransaction, report *SimulationReport) error {
+	if tx.Nonce == nil {
+		return nil
+	}
+
+	nonce := tx.Nonce.ToInt()
+
+	stateNonce, err := client.GetNextNonce(*tx.From)
+	if err != nil {
+		return errors.Wrap(err, "failed to get next nonce")
+	}
+
+	if nonce.Cmp(stateNonce.ToInt()) < 0 {
+		report.warn("nonce %v is less than next nonce %v and already used", nonce, stateNonce)
+		return nil
+	}
+
+	pending, err := client.TxPool().PendingNonceRange(*tx.From)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pending nonce range")
+	}
+
+	expected := stateNonce.ToInt()
+
+	if pending.MinNonce != nil && pending.MaxNonce != nil && pending.MaxNonce.ToInt().Cmp(expected) >= 0 {
+		if nonce.Cmp(pending.MinNonce.ToInt()) >= 0 && nonce.Cmp(pending.MaxNonce.ToInt()) <= 0 {
+			report.warn("nonce %v is used by pending transaction and will replace it", nonce)
+			return nil
+		}
+
+		expected = new(big.Int).Add(pending.MaxNonce.ToInt(), big.NewInt(1))
+	}
+
+	if nonce.Cmp(expected) > 0 {
+		report.warn("nonce %v is greater than expected nonce %v and will not be packed until the gap filled", nonce, expected)
+	}
+
+	return nil
+}
+
+func (client *Client) checkEpochHeight(tx *types.UnsignedTransaction, report *SimulationReport) error {
+	if tx.EpochHeight == nil {
+		return nil
+	}
+
+	epoch, err := client.GetEpochNumber(types.EpochLatestState)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the latest state epoch number")
+	}
+
+	current, height := epoch.ToInt().Uint64(), uint64(*tx.EpochHeight)
+
+	if height+constants.TransactionEpochBound < current || height > current+constants.TransactionEpochBound {
+		report.warn("epoch height %v is out of bound %v of current epoch %v", height, constants.TransactionEpochBound, current)
+	}
+
+	return nil
+}
+
+// revertReason returns the revert reason of execution error returned by node, of which the Error(string) revert
+// data is decoded if possible. It returns false if err is not returned by node, e.g. network or timeout errors.
+func revertReason(err error) (string, bool) {
+	rpcErr, e := utils.ToRpcError(err)
+	if e != nil {
+		return "", false
+	}
+
+	if data, ok := rpcErr.Data.(string); ok {
+		if b, e := hexutil.Decode(data); e == nil {
+			if reason, e := abiutil.DecodeErrData(b); e == nil {
+				return reason, true
+			}
+		}
+	}
+
+	if rpcErr.Data == nil {
+		return rpcErr.Message, true
+	}
+
+	return rpcErr.Error(), true
+}
@@ -0,0 +1,142 @@
+package sdk
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/internal/testutil"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/Conflux-Chain/go-conflux-sdk/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeClient(t *testing.T, provider *testutil.FakeProvider) *Client {
+	client, err := NewClientWithProvider(provider)
+	assert.NoError(t, err)
+
+	client.SetNetworkId(1029)
+	client.SetChainId(1029)
+
+	return client
+}
+
+var (
+	simulateUser     = cfxaddress.MustNewFromHex("0x1000000000000000000000000000000000000001", 1029)
+	simulateContract = cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000002", 1029)
+)
+
+func newSimulateProvider() *testutil.FakeProvider {
+	return testutil.NewFakeProvider(map[string]interface{}{
+		"cfx_estimateGasAndCollateral": map[string]string{"gasLimit": "0x7530", "gasUsed": "0x5208", "storageCollateralized": "0x40"},
+		"cfx_call":                     "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"cfx_checkBalanceAgainstTransaction": map[string]bool{
+			"willPayTxFee": false, "willPayCollateral": true, "isBalanceEnough": true,
+		},
+		"cfx_getBalance":           "0xde0b6b3a7640000", // 1 CFX
+		"cfx_getNextNonce":         "0x5",
+		"txpool_pendingNonceRange": map[string]string{"minNonce": "0x5", "maxNonce": "0x6"},
+		"cfx_epochNumber":          "0x30d40", // 200000
+	})
+}
+
+func TestSimulate(t *testing.T) {
+	provider := newSimulateProvider()
+	client := newFakeClient(t, provider)
+
+	tx := types.UnsignedTransaction{To: &simulateContract}
+	tx.From = &simulateUser
+	tx.GasPrice = types.NewBigInt(1_000_000_000)
+	tx.Nonce = types.NewBigInt(7)
+	tx.EpochHeight = types.NewUint64(100000)
+
+	report, err := client.Simulate(tx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, big.NewInt(30000), report.GasLimit)
+	assert.Equal(t, uint64(64), report.StorageLimit)
+	assert.Equal(t, big.NewInt(30000*1_000_000_000), report.GasFee)
+	assert.True(t, report.GasSponsored)
+	assert.False(t, report.CollateralSponsored)
+
+	// 64 bytes storage collateral only since gas sponsored
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(64), constants.CollateralPerStorageByte), report.TotalCost)
+	assert.Equal(t, "0.0625 CFX", report.TotalCostCFX)
+	assert.Equal(t, report.TotalCost, report.Cost.WorstCase)
+	assert.Equal(t, report.TotalCost, report.Cost.Refundable)
+	assert.True(t, report.IsBalanceEnough)
+	assert.Equal(t, 32, len(report.ReturnData))
+	assert.Empty(t, report.Warnings)
+	assert.True(t, report.Succeeded())
+}
+
+func TestSimulateWarnings(t *testing.T) {
+	provider := newSimulateProvider()
+	provider.Results["cfx_getBalance"] = "0x1"
+	provider.Results["cfx_checkBalanceAgainstTransaction"] = map[string]bool{"willPayTxFee": true, "willPayCollateral": true}
+	provider.Errors["cfx_call"] = newRevertError("denied")
+	provider.Errors["cfx_estimateGasAndCollateral"] = newRevertError("denied")
+	client := newFakeClient(t, provider)
+
+	other := cfxaddress.MustNewFromHex("0x8000000000000000000000000000000000000002", 1)
+
+	tx := types.UnsignedTransaction{To: &other}
+	tx.From = &simulateUser
+	tx.GasPrice = types.NewBigInt(1)
+	tx.Gas = types.NewBigInt(21000)
+	tx.StorageLimit = types.NewUint64(0)
+	tx.Nonce = types.NewBigInt(9)
+	tx.EpochHeight = types.NewUint64(1)
+
+	report, err := client.Simulate(tx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "denied", report.RevertReason)
+	assert.False(t, report.IsBalanceEnough)
+	assert.False(t, report.Succeeded())
+
+	warnings := strings.Join(report.Warnings, "\n")
+	assert.Contains(t, warnings, "network ID 1 of to address")
+	assert.Contains(t, warnings, "balance")
+	assert.Contains(t, warnings, "nonce 9 is greater than expected nonce 7")
+	assert.Contains(t, warnings, "epoch height 1 is out of bound")
+
+	tx.Nonce = types.NewBigInt(6)
+	report, err = client.Simulate(tx)
+	assert.NoError(t, err)
+	assert.Contains(t, strings.Join(report.Warnings, "\n"), "used by pending transaction")
+
+	tx.Nonce = types.NewBigInt(4)
+	report, err = client.Simulate(tx)
+	assert.NoError(t, err)
+	assert.Contains(t, strings.Join(report.Warnings, "\n"), "already used")
+}
+
+func TestSimulateRequestError(t *testing.T) {
+	provider := newSimulateProvider()
+	provider.Errors["cfx_call"] = errors.New("connection refused")
+	client := newFakeClient(t, provider)
+
+	tx := types.UnsignedTransaction{To: &simulateContract}
+	tx.From = &simulateUser
+
+	_, err := client.Simulate(tx)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+// newRevertError creates the execution error returned by node with Error(string) revert data
+func newRevertError(reason string) error {
+	data, _ := abi.Arguments{{Type: abiStringType}}.Pack(reason)
+	return &utils.RpcError{
+		Code:    -32015,
+		Message: "Transaction reverted",
+		Data:    hexutil.Encode(append([]byte{0x08, 0xc3, 0x79, 0xa0}, data...)),
+	}
+}
+
+var abiStringType, _ = abi.NewType("string", "", nil)
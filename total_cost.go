@@ -0,0 +1,243 @@
+package sdk
+
+import (
+	"math/big"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/cfxaddress"
+	"github.com/Conflux-Chain/go-conflux-sdk/types/unit"
+	"github.com/pkg/errors"
+)
+
+// TotalCost is the estimated CFX spent by the sender of transaction, including value, gas fee and
+// storage collateral that not covered by sponsor.
+type TotalCost struct {
+	Estimate *types.Estimate // nil if failed to estimate, e.g. simulated transaction reverted
+
+	GasLimit          *big.Int
+	GasPrice          *big.Int // gas price of legacy transaction, or max fee per gas of 1559 transaction
+	EffectiveGasPrice *big.Int // expected gas price, which is base fee plus priority fee for 1559 transaction
+	StorageLimit      uint64
+
+	MaxGasFee         *big.Int // GasLimit * GasPrice
+	ExpectedGasFee    *big.Int // charged gas * EffectiveGasPrice, at least 3/4 of gas limit is charged
+	StorageCollateral *big.Int // StorageLimit * constants.CollateralPerStorageByte
+
+	GasSponsored        bool
+	CollateralSponsored bool
+	SponsoredGasFee     *big.Int // gas fee paid by the gas sponsor in worst case
+	SponsoredCollateral *big.Int // storage collateral paid by the collateral sponsor
+
+	WorstCase  *big.Int // value, MaxGasFee and StorageCollateral paid by sender
+	Expected   *big.Int // value, ExpectedGasFee and StorageCollateral paid by sender
+	Refundable *big.Int // storage collateral paid by sender, which will be refunded after storage released
+}
+
+// TotalCostInDrip is the TotalCost in unit.Drip.
+type TotalCostInDrip struct {
+	WorstCase           *unit.Drip
+	Expected            *unit.Drip
+	Refundable          *unit.Drip
+	SponsoredGasFee     *unit.Drip
+	SponsoredCollateral *unit.Drip
+}
+
+// TotalCostDisplay is the TotalCost in CFX display strings, e.g. "0.0625 CFX".
+type TotalCostDisplay struct {
+	WorstCase           string
+	Expected            string
+	Refundable          string
+	SponsoredGasFee     string
+	SponsoredCollateral string
+}
+
+// InDrip converts the total cost to unit.Drip.
+func (c *TotalCost) InDrip() TotalCostInDrip {
+	return TotalCostInDrip{
+		WorstCase:           unit.NewDrip(c.WorstCase),
+		Expected:            unit.NewDrip(c.Expected),
+		Refundable:          unit.NewDrip(c.Refundable),
+		SponsoredGasFee:     unit.NewDrip(c.SponsoredGasFee),
+		SponsoredCollateral: unit.NewDrip(c.SponsoredCollateral),
+	}
+}
+
+// Display converts the total cost to CFX display strings for UIs.
+func (c *TotalCost) Display() TotalCostDisplay {
+	return TotalCostDisplay{
+		WorstCase:           formatCFX(c.WorstCase),
+		Expected:            formatCFX(c.Expected),
+		Refundable:          formatCFX(c.Refundable),
+		SponsoredGasFee:     formatCFX(c.SponsoredGasFee),
+		SponsoredCollateral: formatCFX(c.SponsoredCollateral),
+	}
+}
+
+// EstimateTotalCost estimates the worst-case and expected CFX spent by the sender of transaction, the refundable
+// storage collateral and the portions covered by sponsor of the contract.
+//
+// The gas limit and storage limit of transaction are used if set, otherwise the estimated ones are used. The sponsor
+// coverage is checked by node with CheckBalanceAgainstTransaction, which takes the sponsor whitelist, gas bound,
+// sponsor balances and storage points into account.
+func (client *Client) EstimateTotalCost(tx types.UnsignedTransaction) (*TotalCost, error) {
+	if tx.From == nil {
+		return nil, errors.New("from address is required")
+	}
+
+	callReq := new(types.CallRequest)
+	callReq.FillByUnsignedTx(&tx)
+
+	estimate, err := client.EstimateGasAndCollateral(*callReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to estimate gas and collateral")
+	}
+
+	return client.totalCostOf(&tx, &estimate)
+}
+
+// totalCostOf computes the total cost of transaction with the estimate, which could be nil if failed to estimate.
+func (client *Client) totalCostOf(tx *types.UnsignedTransaction, estimate *types.Estimate) (*TotalCost, error) {
+	cost := TotalCost{
+		Estimate: estimate,
+		GasLimit: new(big.Int),
+	}
+
+	switch {
+	case tx.Gas != nil:
+		cost.GasLimit = tx.Gas.ToInt()
+	case estimate != nil:
+		cost.GasLimit = estimate.GasLimit.ToInt()
+	}
+
+	switch {
+	case tx.StorageLimit != nil:
+		cost.StorageLimit = uint64(*tx.StorageLimit)
+	case estimate != nil:
+		cost.StorageLimit = estimate.StorageCollateralized.ToInt().Uint64()
+	}
+
+	gasUsed := cost.GasLimit
+	if estimate != nil {
+		gasUsed = estimate.GasUsed.ToInt()
+	}
+
+	var err error
+	if cost.GasPrice, cost.EffectiveGasPrice, err = client.gasPriceOf(tx); err != nil {
+		return nil, err
+	}
+
+	cost.MaxGasFee = new(big.Int).Mul(cost.GasLimit, cost.GasPrice)
+	cost.ExpectedGasFee = new(big.Int).Mul(chargedGas(gasUsed, cost.GasLimit), cost.EffectiveGasPrice)
+	cost.StorageCollateral = new(big.Int).Mul(new(big.Int).SetUint64(cost.StorageLimit), constants.CollateralPerStorageByte)
+
+	if err := client.checkSponsor(tx, &cost); err != nil {
+		return nil, err
+	}
+
+	cost.SponsoredGasFee, cost.SponsoredCollateral = new(big.Int), new(big.Int)
+	cost.WorstCase, cost.Expected, cost.Refundable = new(big.Int), new(big.Int), new(big.Int)
+
+	if tx.Value != nil {
+		cost.WorstCase.Add(cost.WorstCase, tx.Value.ToInt())
+		cost.Expected.Add(cost.Expected, tx.Value.ToInt())
+	}
+
+	if cost.GasSponsored {
+		cost.SponsoredGasFee.Set(cost.MaxGasFee)
+	} else {
+		cost.WorstCase.Add(cost.WorstCase, cost.MaxGasFee)
+		cost.Expected.Add(cost.Expected, cost.ExpectedGasFee)
+	}
+
+	if cost.CollateralSponsored {
+		cost.SponsoredCollateral.Set(cost.StorageCollateral)
+	} else {
+		cost.WorstCase.Add(cost.WorstCase, cost.StorageCollateral)
+		cost.Expected.Add(cost.Expected, cost.StorageCollateral)
+		cost.Refundable.Set(cost.StorageCollateral)
+	}
+
+	return &cost, nil
+}
+
+// gasPriceOf returns the max gas price and the expected effective gas price of transaction. The fee suggested
+// by client is used if gas price of transaction not set.
+func (client *Client) gasPriceOf(tx *types.UnsignedTransaction) (maxPrice, effectivePrice *big.Int, err error) {
+	if tx.GasPrice != nil {
+		return tx.GasPrice.ToInt(), tx.GasPrice.ToInt(), nil
+	}
+
+	var priority *big.Int
+
+	if tx.MaxFeePerGas != nil {
+		maxPrice = tx.MaxFeePerGas.ToInt()
+		if tx.MaxPriorityFeePerGas != nil {
+			priority = tx.MaxPriorityFeePerGas.ToInt()
+		}
+	} else {
+		fee, err := client.SuggestFee()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to suggest fee")
+		}
+
+		if !fee.IsSupport1559() {
+			return fee.GasPrice, fee.GasPrice, nil
+		}
+
+		maxPrice, priority = fee.MaxFeePerGas, fee.MaxPriorityFeePerGas
+	}
+
+	market, err := client.getFeeOracle().Market()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get fee market")
+	}
+
+	if market.BaseFeePerGas == nil {
+		return maxPrice, maxPrice, nil
+	}
+
+	if priority == nil {
+		priority = market.MaxPriorityFeePerGas
+	}
+
+	effectivePrice = new(big.Int).Add(market.BaseFeePerGas, priority)
+	if effectivePrice.Cmp(maxPrice) > 0 {
+		effectivePrice = maxPrice
+	}
+
+	return maxPrice, effectivePrice, nil
+}
+
+// checkSponsor checks whether the gas fee and storage collateral of transaction are covered by sponsor.
+func (client *Client) checkSponsor(tx *types.UnsignedTransaction, cost *TotalCost) error {
+	// only contract call could be sponsored
+	if tx.To == nil || tx.To.GetAddressType() != cfxaddress.AddressTypeContract {
+		return nil
+	}
+
+	res, err := client.CheckBalanceAgainstTransaction(*tx.From, *tx.To, types.NewBigIntByRaw(cost.GasLimit),
+		types.NewBigIntByRaw(cost.GasPrice), types.NewBigInt(cost.StorageLimit))
+	if err != nil {
+		return errors.Wrap(err, "failed to check balance against transaction")
+	}
+
+	cost.GasSponsored, cost.CollateralSponsored = !res.WillPayTxFee, !res.WillPayCollateral
+
+	return nil
+}
+
+// chargedGas returns the gas charged by transaction, which refunds at most 1/4 of gas limit.
+func chargedGas(gasUsed, gasLimit *big.Int) *big.Int {
+	minCharged := new(big.Int).Sub(gasLimit, new(big.Int).Div(gasLimit, big.NewInt(4)))
+	if gasUsed.Cmp(minCharged) < 0 {
+		return minCharged
+	}
+
+	return gasUsed
+}
+
+// formatCFX formats the value in drip as CFX display string, e.g. "0.0625 CFX".
+func formatCFX(value *big.Int) string {
+	return unit.NewDrip(value).FormatCFX().String() + " CFX"
+}
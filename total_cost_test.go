@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Conflux-Chain/go-conflux-sdk/constants"
+	"github.com/Conflux-Chain/go-conflux-sdk/internal/testutil"
+	"github.com/Conflux-Chain/go-conflux-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTotalCostProvider() *testutil.FakeProvider {
+	provider := newSimulateProvider()
+	provider.Results["cfx_gasPrice"] = "0x1"
+	provider.Results["cfx_getBlockByEpochNumber"] = map[string]string{"baseFeePerGas": "0x3b9aca00"}
+	provider.Results["cfx_maxPriorityFeePerGas"] = "0x2"
+	return provider
+}
+
+func TestEstimateTotalCost(t *testing.T) {
+	client := newFakeClient(t, newTotalCostProvider())
+
+	tx := types.UnsignedTransaction{To: &simulateContract}
+	tx.From = &simulateUser
+	tx.Value = types.NewBigInt(100)
+	tx.GasPrice = types.NewBigInt(1_000_000_000)
+
+	cost, err := client.EstimateTotalCost(tx)
+	assert.NoError(t, err)
+
+	collateral := new(big.Int).Mul(big.NewInt(64), constants.CollateralPerStorageByte)
+
+	assert.True(t, cost.GasSponsored)
+	assert.False(t, cost.CollateralSponsored)
+	assert.Equal(t, big.NewInt(30000*1_000_000_000), cost.SponsoredGasFee)
+	assert.Equal(t, big.NewInt(0), cost.SponsoredCollateral)
+	assert.Equal(t, new(big.Int).Add(collateral, big.NewInt(100)), cost.WorstCase)
+	assert.Equal(t, cost.WorstCase, cost.Expected)
+	assert.Equal(t, collateral, cost.Refundable)
+
+	assert.Equal(t, "0.0625 CFX", cost.Display().Refundable)
+	assert.Equal(t, "0.00003 CFX", cost.Display().SponsoredGasFee)
+	assert.Equal(t, collateral, cost.InDrip().Refundable.BigInt())
+}
+
+func TestEstimateTotalCostNotSponsored(t *testing.T) {
+	provider := newTotalCostProvider()
+	// e.g. the max gas fee exceeds the sponsor gas bound
+	provider.Results["cfx_checkBalanceAgainstTransaction"] = map[string]bool{"willPayTxFee": true, "willPayCollateral": true}
+	client := newFakeClient(t, provider)
+
+	tx := types.UnsignedTransaction{To: &simulateContract}
+	tx.From = &simulateUser
+	tx.MaxFeePerGas = types.NewBigInt(2_000_000_000)
+
+	cost, err := client.EstimateTotalCost(tx)
+	assert.NoError(t, err)
+
+	assert.False(t, cost.GasSponsored)
+	assert.Equal(t, big.NewInt(2_000_000_000), cost.GasPrice)
+	assert.Equal(t, big.NewInt(1_000_000_002), cost.EffectiveGasPrice)
+	assert.Equal(t, big.NewInt(30000*2_000_000_000), cost.MaxGasFee)
+	// gas used 21000 is less than 3/4 of gas limit 30000
+	assert.Equal(t, big.NewInt(22500*1_000_000_002), cost.ExpectedGasFee)
+
+	collateral := new(big.Int).Mul(big.NewInt(64), constants.CollateralPerStorageByte)
+	assert.Equal(t, new(big.Int).Add(collateral, cost.MaxGasFee), cost.WorstCase)
+	assert.Equal(t, new(big.Int).Add(collateral, cost.ExpectedGasFee), cost.Expected)
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+// CIP112Epoch is the epoch number on mainnet since when the custom fields of block header are RLP encoded as bytes
+const CIP112Epoch = uint64(79050000)
+
+// EncodeForHash returns the RLP encoded block header that used to compute block hash.
+//
+// The evmBaseFeePerGas is the base fee of eSpace block in the same epoch, which is required if
+// the BaseFeePerGas of block header is not nil, namely CIP-1559 enabled.
+func (bh *BlockHeader) EncodeForHash(evmBaseFeePerGas *big.Int) ([]byte, error) {
+	var adaptive uint64
+	if bh.Adaptive {
+		adaptive = 1
+	}
+
+	referees := make([]common.Hash, 0, len(bh.RefereeHashes))
+	for _, v := range bh.RefereeHashes {
+		referees = append(referees, *v.ToCommonHash())
+	}
+
+	miner, _, err := bh.Miner.ToCommon()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to convert miner address")
+	}
+
+	list := []interface{}{
+		bh.ParentHash.ToCommonHash(),
+		bh.Height.ToInt(),
+		bh.Timestamp.ToInt(),
+		miner,
+		bh.TransactionsRoot.ToCommonHash(),
+		bh.DeferredStateRoot.ToCommonHash(),
+		bh.DeferredReceiptsRoot.ToCommonHash(),
+		bh.DeferredLogsBloomHash.ToCommonHash(),
+		bh.Blame,
+		bh.Difficulty.ToInt(),
+		adaptive,
+		bh.GasLimit.ToInt(),
+		referees,
+		bh.Nonce.ToInt(),
+	}
+
+	// simulate RLP encoding for rust Option type
+	if bh.PosReference != nil {
+		list = append(list, []interface{}{bh.PosReference.ToCommonHash()})
+	}
+
+	if bh.BaseFeePerGas != nil {
+		if evmBaseFeePerGas == nil {
+			return nil, errors.New("evm base fee per gas is required for block with base fee")
+		}
+
+		list = append(list, []interface{}{
+			[]interface{}{bh.BaseFeePerGas.ToInt(), evmBaseFeePerGas},
+		})
+	}
+
+	// custom fields are RLP encoded values before CIP-112
+	isCip112 := bh.EpochNumber != nil && bh.EpochNumber.ToInt().Uint64() >= CIP112Epoch
+	for _, v := range bh.Custom {
+		if isCip112 {
+			list = append(list, v.ToBytes())
+		} else {
+			list = append(list, rlp.RawValue(v.ToBytes()))
+		}
+	}
+
+	encoded, err := rlp.EncodeToBytes(list)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to RLP encode block header")
+	}
+
+	return encoded, nil
+}
+
+// ComputeHash computes the block hash by block header fields, see EncodeForHash for evmBaseFeePerGas.
+func (bh *BlockHeader) ComputeHash(evmBaseFeePerGas *big.Int) (Hash, error) {
+	encoded, err := bh.EncodeForHash(evmBaseFeePerGas)
+	if err != nil {
+		return "", err
+	}
+
+	return Hash(crypto.Keccak256Hash(encoded).Hex()), nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockHeaderComputeHash(t *testing.T) {
+	table := []struct {
+		json             string
+		evmBaseFeePerGas *big.Int
+	}{
+		// mainnet block
+		{
+			json: `{"hash":"0x11b5c88b4e42fcf95cb1454d5de03d7f31fb59f80df1e49c0723f4f86516ef01","parentHash":"0x372e5820b5f6cd0ffe03c27525694daf28da0ab236cbf961e41aa2e24880bcf7","height":"0xf7cf20","miner":"cfx:aamwwx800rcw63n42kbehesuukjdjcnu4ueu84nhp5","deferredStateRoot":"0x1b04a03817a85ed558e660fb1a0b5b9a640acced757903f8f947cbfbca4cee9a","deferredReceiptsRoot":"0x30ce3b69dadfb10545672f166c953825cccfcb2fb2b6c9c3e205ed2d6f9e8ac1","deferredLogsBloomHash":"0x730d2fa11bef8d14e1f35948a6bdbd09e6ec7fb148ebe2fedae76e5af8cb5d4b","blame":"0x0","transactionsRoot":"0x4bbeac6fa3502f7d2e78eed5caec47ffefad6ec5bb85e84d02f682a05b81de14","epochNumber":"0xf7cf20","blockNumber":"0xf7cf20","gasLimit":"0x1c9c380","gasUsed":"0x4c136","timestamp":"0x60b853f9","difficulty":"0x1371539f68f","powQuality":"0x1db83607fe3","refereeHashes":[],"adaptive":false,"nonce":"0x11f684c0d194b2a3","size":"0x421","custom":["0x01"],"posReference":null}`,
+		},
+		// mainnet block with referees
+		{
+			json: `{"hash":"0x26f15dc6f353485cdfb1b370becc4abfdacbd36e39c3f9f42be724fe4073cfeb","parentHash":"0xa0c5975f77a557ab65eb1a137de52cd9d9a88f4b36add157ec3c0e2edce1351f","height":"0xf7cf1c","miner":"cfx:aamwwx800rcw63n42kbehesuukjdjcnu4ueu84nhp5","deferredStateRoot":"0x085123da2df1ab4d0af41b99396280ea8f7778048f78bc141118ca1b163d0d75","deferredReceiptsRoot":"0x7976c478fc5ae2d2abe95cd7ef488b439fbac961abedf4a1b0cdee4be6bab27e","deferredLogsBloomHash":"0xd397b3b043d87fcd6fad1291ff0bfd16401c274896d8c63a923727f077b8e0b5","blame":"0x0","transactionsRoot":"0xbf9add52641cdeb9fec7fc8bbacfaf71592c37df34b1f36220003af8797dfb41","epochNumber":"0xf7cf1c","blockNumber":"0xf7cf1c","gasLimit":"0x1c9c380","gasUsed":"0x2ef98","timestamp":"0x60b853f5","difficulty":"0x1371539f68f","powQuality":"0x204fb171a2c","refereeHashes":["0xd28aeb7aea7012a58d776b89f03bbed85d7ebd75e445323e02dcf28af8753750","0xa20f6152fb3434c0c1c3ce8176044476e4baa2db18d0cea9185932e7072fd0ac"],"adaptive":false,"nonce":"0xf1c5c1596190023b","size":"0x24a","custom":["0x01"],"posReference":null}`,
+		},
+		// block with pos reference and base fee
+		{
+			json:             `{"hash":"0x7fab5518a46d13a4d8b5c32d953872f41225a6bd5876781e97b094ef3ff03d4f","parentHash":"0xdda233f61d4c2b6b1526831bf9cd48144c3032cd864ca2779c329760edd6a283","height":"0x14e997","miner":"net8888:aajaaaaaaaaaaaaaaaaaaaaaaaaaaaaabux08ucy6f","deferredStateRoot":"0xaed49a6fe6bfb3c98d8b767080161590ed08e516a10b26b7fbe5b3a6f86038bf","deferredReceiptsRoot":"0x09f8709ea9f344a810811a373b30861568f5686e649d6177fd92ea2db7477508","deferredLogsBloomHash":"0xd397b3b043d87fcd6fad1291ff0bfd16401c274896d8c63a923727f077b8e0b5","blame":"0x0","transactionsRoot":"0x32c3c4f7d6b706cd4bdb158a58b441a6bf05847dfe33edf78b7cd2f708197805","epochNumber":"0x14e997","blockNumber":"0x178335","gasLimit":"0x3938700","gasUsed":"0x62d4","baseFeePerGas":"0x3b9aca00","timestamp":"0x66690a60","difficulty":"0x2cf","powQuality":"0x565","refereeHashes":[],"adaptive":false,"nonce":"0x659c4c09269d4b2c","size":"0xb4","custom":["0x04"],"posReference":"0x181c59f93232a2dca79123f3f23396920ec779516f6b86f0e94a84e7803588e0"}`,
+			evmBaseFeePerGas: big.NewInt(20_000_000_000),
+		},
+	}
+
+	for _, v := range table {
+		var header BlockHeader
+		assert.NoError(t, json.Unmarshal([]byte(v.json), &header))
+
+		hash, err := header.ComputeHash(v.evmBaseFeePerGas)
+		assert.NoError(t, err)
+		assert.Equal(t, header.Hash, hash)
+
+		// tampered header
+		header.Nonce.ToInt().Add(header.Nonce.ToInt(), big.NewInt(1))
+		hash, err = header.ComputeHash(v.evmBaseFeePerGas)
+		assert.NoError(t, err)
+		assert.NotEqual(t, header.Hash, hash)
+	}
+
+	// evm base fee required
+	var header BlockHeader
+	assert.NoError(t, json.Unmarshal([]byte(table[2].json), &header))
+	_, err := header.ComputeHash(nil)
+	assert.Error(t, err)
+}
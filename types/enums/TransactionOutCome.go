@@ -1,5 +1,7 @@
 package enums
 
+import "fmt"
+
 type TransactionOutcome uint8
 
 const (
@@ -23,3 +25,39 @@ const (
 	EVM_SPACE_SUCCESS
 	EVM_SPACE_SKIPPED = 0xff
 )
+
+func (o TransactionOutcome) String() string {
+	switch o {
+	case TRANSACTION_OUTCOME_SUCCESS:
+		return "SUCCESS"
+	case TRANSACTION_OUTCOME_FAILURE:
+		return "FAILURE"
+	case TRANSACTION_OUTCOME_SKIPPED:
+		return "SKIPPED"
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(o))
+}
+
+func (o NativeSpaceOutcome) String() string {
+	switch o {
+	case NATIVE_SPACE_SUCCESS:
+		return "NATIVE_SPACE_SUCCESS"
+	case NATIVE_SPACE_EXCEPTION_WITH_NONCE_BUMPING:
+		return "NATIVE_SPACE_EXCEPTION_WITH_NONCE_BUMPING"
+	case NATIVE_SPACE_EXCEPTION_WITHOUT_NONCE_BUMPING:
+		return "NATIVE_SPACE_EXCEPTION_WITHOUT_NONCE_BUMPING"
+	}
+	return fmt.Sprintf("NATIVE_SPACE_UNKNOWN(%d)", uint8(o))
+}
+
+func (o EvmSpaceOutcome) String() string {
+	switch o {
+	case EVM_SPACE_FAIL:
+		return "EVM_SPACE_FAIL"
+	case EVM_SPACE_SUCCESS:
+		return "EVM_SPACE_SUCCESS"
+	case EVM_SPACE_SKIPPED:
+		return "EVM_SPACE_SKIPPED"
+	}
+	return fmt.Sprintf("EVM_SPACE_UNKNOWN(%d)", uint8(o))
+}
@@ -1,6 +1,7 @@
 package postypes
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"math/big"
 
@@ -40,6 +41,16 @@ type Committee struct {
 	uncompressedKeys map[common.Hash]hexutil.Bytes // pos account => uncompressed BLS public key
 }
 
+// NewCommittee creates a committee with epoch state and uncompressed BLS public keys of validators
+func NewCommittee(state *EpochState, uncompressedKeys map[common.Hash]hexutil.Bytes) Committee {
+	return Committee{state, uncompressedKeys}
+}
+
+// State returns the epoch state of committee
+func (c *Committee) State() *EpochState {
+	return c.state
+}
+
 func (c *Committee) GetPublicKey(account common.Hash) (pubKey []byte, ok bool) {
 	pubKey, ok = c.uncompressedKeys[account]
 	return
@@ -62,6 +73,20 @@ func (info *LedgerInfoWithSignatures) EncodeBCS() []byte {
 	return encoded
 }
 
+// DecodeLedgerInfoBCS decodes the ledger info from BCS encoded data with prefix, e.g. returned by EncodeBCS.
+func DecodeLedgerInfoBCS(encoded []byte) (*LedgerInfo, error) {
+	if !bytes.HasPrefix(encoded, bcsPrefix) {
+		return nil, errors.New("BCS prefix mismatch")
+	}
+
+	var info LedgerInfo
+	if err := bcs.DecodeBytes(encoded[len(bcsPrefix):], &info); err != nil {
+		return nil, errors.WithMessage(err, "Failed to decode BCS encoded ledger info")
+	}
+
+	return &info, nil
+}
+
 func (info *LedgerInfoWithSignatures) Verify(committee Committee) (bool, error) {
 	if len(info.Signatures) == 0 {
 		return false, nil
@@ -303,3 +328,31 @@ func big2OSP(x *big.Int, len int) []byte {
 
 	return result
 }
+
+// SignBLS signs msg with the BLS private key and returns the uncompressed signature in 192 bytes,
+// the message is hashed into G2 point in the same way as the PoS ledger info signatures.
+func SignBLS(privateKey *big.Int, msg []byte) ([]byte, error) {
+	hash, err := hashToCurve(msg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to hash message into G2 point")
+	}
+
+	g2 := bls12381.NewG2()
+	signature := g2.MulScalarBig(g2.New(), hash, privateKey)
+	return g2.ToBytes(signature), nil
+}
+
+// VerifyBLS verifies the uncompressed BLS signature of msg against the uncompressed BLS public key in 96 bytes
+func VerifyBLS(signature []byte, publicKey []byte, msg []byte) (bool, error) {
+	pubKey, err := bls12381.NewG1().FromBytes(publicKey)
+	if err != nil {
+		return false, errors.WithMessage(err, "Failed to decode public key to G1 point")
+	}
+
+	hash, err := hashToCurve(msg)
+	if err != nil {
+		return false, errors.WithMessage(err, "Failed to hash message into G2 point")
+	}
+
+	return verifyBLS(signature, pubKey, hash)
+}
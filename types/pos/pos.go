@@ -2,6 +2,7 @@ package postypes
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/Conflux-Chain/go-conflux-sdk/utils"
 	"sort"
 
@@ -133,7 +134,7 @@ func (b *Transaction) UnmarshalJSON(data []byte) error {
 		BlockNumber *hexutil.Uint64 `json:"blockNumber"`
 		Timestamp   *hexutil.Uint64 `json:"timestamp"`
 		Number      hexutil.Uint64  `json:"number"`
-		Payload     interface{}     `json:"payload"`
+		Payload     json.RawMessage `json:"payload"`
 		Status      *string         `json:"status"`
 		Type        string          `json:"type"`
 	}
@@ -147,33 +148,13 @@ func (b *Transaction) UnmarshalJSON(data []byte) error {
 	*b = Transaction{tmpTx.Hash, tmpTx.From, tmpTx.BlockHash, tmpTx.BlockNumber,
 		tmpTx.Timestamp, tmpTx.Number, nil, tmpTx.Status, tmpTx.Type}
 
-	if tmpTx.Payload != nil {
-		marshaled, err := utils.JsonMarshal(tmpTx.Payload)
+	if len(tmpTx.Payload) > 0 && string(tmpTx.Payload) != "null" {
+		payload, err := UnmarshalTransactionPayload(tmpTx.Type, tmpTx.Payload)
 		if err != nil {
 			return errors.WithStack(err)
 		}
 
-		realPayload := TransactionPayload{}
-		realPayload.SetTransactionType(tmpTx.Type)
-		switch tmpTx.Type {
-		case "Election":
-			err = utils.JsonUnmarshal(marshaled, &realPayload.ElectionPayload)
-		case "Retire":
-			err = utils.JsonUnmarshal(marshaled, &realPayload.RetirePayload)
-		case "Register":
-			err = utils.JsonUnmarshal(marshaled, &realPayload.RegisterPayload)
-		case "UpdateVotingPower":
-			err = utils.JsonUnmarshal(marshaled, &realPayload.UpdateVotingPowerPayload)
-		case "PivotDecision":
-			err = utils.JsonUnmarshal(marshaled, &realPayload.PivotBlockDecision)
-		case "Dispute":
-			err = utils.JsonUnmarshal(marshaled, &realPayload.DisputePayload)
-		}
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		b.Payload = &realPayload
+		b.Payload = payload
 	}
 
 	return nil
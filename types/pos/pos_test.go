@@ -7,6 +7,7 @@ import (
 
 	"github.com/Conflux-Chain/go-conflux-sdk/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -219,3 +220,70 @@ func TestUnmarshalAccount(t *testing.T) {
 	assert.NoError(t, err)
 	fmt.Printf("acc: %v\n", acc)
 }
+
+func TestLedgerInfoBCSRoundTrip(t *testing.T) {
+	vrfKey := hexutil.Bytes{3, 4}
+	info := LedgerInfoWithSignatures{
+		LedgerInfo: LedgerInfo{
+			CommitInfo: BlockInfo{
+				Epoch:           2,
+				Round:           10,
+				Id:              common.HexToHash("0x01").Bytes(),
+				ExecutedStateId: common.HexToHash("0x02").Bytes(),
+				Version:         100,
+				TimestampUsecs:  1000,
+				NextEpochState: &EpochState{
+					Epoch: 3,
+					Verifier: ValidatorVerifier{
+						AddressToValidatorInfo: map[common.Hash]ValidatorConsensusInfo{
+							common.HexToHash("0x0a"): {PublicKey: hexutil.Bytes{1, 2}, VrfPublicKey: &vrfKey, VotingPower: 1},
+							common.HexToHash("0x0b"): {PublicKey: hexutil.Bytes{5, 6}, VotingPower: 2},
+						},
+						QuorumVotingPower: 3,
+						TotalVotingPower:  3,
+					},
+					VrfSeed: hexutil.Bytes{7},
+				},
+				Pivot: &PivotBlockDecision{
+					Height:    200,
+					BlockHash: H256(common.HexToHash("0x03").Hex()),
+				},
+			},
+			ConsensusDataHash: common.HexToHash("0x04").Bytes(),
+		},
+	}
+
+	decoded, err := DecodeLedgerInfoBCS(info.EncodeBCS())
+	assert.NoError(t, err)
+	assert.Equal(t, info.LedgerInfo, *decoded)
+
+	// without prefix
+	_, err = DecodeLedgerInfoBCS(info.EncodeBCS()[len(bcsPrefix):])
+	assert.Error(t, err)
+}
+
+func TestTransactionPayloadValue(t *testing.T) {
+	var tx Transaction
+	data := genTxJsonWithPayload(`"payload": {
+		"nodeId": "0xe3532f3e329b75d738d46c3356a2cbd5cd75c98b13416c5530bb05db3e1a1d89",
+		"votes": "0x1"
+	}`, TransactionTypeRetire)
+	assert.NoError(t, utils.JsonUnmarshal([]byte(data), &tx))
+	assert.Equal(t, TransactionTypeRetire, tx.Payload.TransactionType())
+
+	switch payload := tx.Payload.Value().(type) {
+	case *RetirePayload:
+		assert.Equal(t, hexutil.Uint64(1), payload.Votes)
+	default:
+		t.Fatalf("unexpected payload type %T", payload)
+	}
+
+	// no payload
+	data = genTxJsonWithPayload(`"payload": null`, "BlockMetadata")
+	assert.NoError(t, utils.JsonUnmarshal([]byte(data), &tx))
+	assert.Nil(t, tx.Payload.Value())
+
+	// invalid payload
+	_, err := UnmarshalTransactionPayload(TransactionTypeElection, []byte(`{"targetTerm": 1}`))
+	assert.Error(t, err)
+}
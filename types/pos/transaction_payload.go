@@ -8,6 +8,16 @@ import (
 	"github.com/pkg/errors"
 )
 
+// PoS transaction types with payload
+const (
+	TransactionTypeElection          = "Election"
+	TransactionTypeRetire            = "Retire"
+	TransactionTypeRegister          = "Register"
+	TransactionTypeUpdateVotingPower = "UpdateVotingPower"
+	TransactionTypePivotDecision     = "PivotDecision"
+	TransactionTypeDispute           = "Dispute"
+)
+
 type TransactionPayload struct {
 	transactionType string
 
@@ -19,28 +29,91 @@ type TransactionPayload struct {
 	DisputePayload
 }
 
+// UnmarshalTransactionPayload decodes the JSON payload of specified PoS transaction type.
+//
+// Note, payload of unknown transaction type will be ignored, and Value() returns nil in this case.
+func UnmarshalTransactionPayload(txType string, data []byte) (*TransactionPayload, error) {
+	payload := TransactionPayload{transactionType: txType}
+
+	var err error
+	switch txType {
+	case TransactionTypeElection:
+		err = utils.JsonUnmarshal(data, &payload.ElectionPayload)
+	case TransactionTypeRetire:
+		err = utils.JsonUnmarshal(data, &payload.RetirePayload)
+	case TransactionTypeRegister:
+		err = utils.JsonUnmarshal(data, &payload.RegisterPayload)
+	case TransactionTypeUpdateVotingPower:
+		err = utils.JsonUnmarshal(data, &payload.UpdateVotingPowerPayload)
+	case TransactionTypePivotDecision:
+		err = utils.JsonUnmarshal(data, &payload.PivotBlockDecision)
+	case TransactionTypeDispute:
+		err = utils.JsonUnmarshal(data, &payload.DisputePayload)
+	}
+
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to unmarshal %v payload", txType)
+	}
+
+	return &payload, nil
+}
+
 func (t *TransactionPayload) SetTransactionType(txType string) {
 	t.transactionType = txType
 }
 
+// TransactionType returns the PoS transaction type of payload.
+func (t *TransactionPayload) TransactionType() string {
+	return t.transactionType
+}
+
+// Value returns the typed payload for type switch, which is one of *ElectionPayload, *RetirePayload,
+// *RegisterPayload, *UpdateVotingPowerPayload, *PivotBlockDecision and *DisputePayload.
+//
+// It returns nil if payload is nil or of unknown transaction type.
+func (t *TransactionPayload) Value() interface{} {
+	if t == nil {
+		return nil
+	}
+
+	switch t.transactionType {
+	case TransactionTypeElection:
+		return &t.ElectionPayload
+	case TransactionTypeRetire:
+		return &t.RetirePayload
+	case TransactionTypeRegister:
+		return &t.RegisterPayload
+	case TransactionTypeUpdateVotingPower:
+		return &t.UpdateVotingPowerPayload
+	case TransactionTypePivotDecision:
+		return &t.PivotBlockDecision
+	case TransactionTypeDispute:
+		return &t.DisputePayload
+	}
+
+	return nil
+}
+
 func (b TransactionPayload) MarshalJSON() ([]byte, error) {
 	switch b.transactionType {
-	case "Election":
+	case TransactionTypeElection:
 		return utils.JsonMarshal(b.ElectionPayload)
-	case "Retire":
+	case TransactionTypeRetire:
 		return utils.JsonMarshal(b.RetirePayload)
-	case "Register":
+	case TransactionTypeRegister:
 		return utils.JsonMarshal(b.RegisterPayload)
-	case "UpdateVotingPower":
+	case TransactionTypeUpdateVotingPower:
 		return utils.JsonMarshal(b.UpdateVotingPowerPayload)
-	case "PivotDecision":
+	case TransactionTypePivotDecision:
 		return utils.JsonMarshal(b.PivotBlockDecision)
-	case "Dispute":
+	case TransactionTypeDispute:
 		return utils.JsonMarshal(b.DisputePayload)
 	}
 	return nil, nil
 }
 
+// UnmarshalJSON is not supported, because the transaction type is required, please use
+// UnmarshalTransactionPayload instead.
 func (b *TransactionPayload) UnmarshalJSON(data []byte) error {
 	return errors.New("not support unmarshal TransactionPayload directly, because need transactionType info")
 }